@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/exporter"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/organizations"
+)
+
+type targetSetter interface {
+	SetTargets(targets ...exporter.Target)
+}
+
+// accountDiscovery discovers accounts of the AWS organization and updates targets of the collector
+type accountDiscovery struct {
+	logger         *slog.Logger
+	configuration  exporterConfig
+	clients        awsClientsConfig
+	client         organizations.OrganizationsClient
+	collector      targetSetter
+	staticTargets  []exporter.Target
+	accountTargets map[string][]exporter.Target // discovered account ID => targets
+}
+
+func newAccountDiscovery(logger *slog.Logger, configuration exporterConfig, clients awsClientsConfig, client organizations.OrganizationsClient, collector targetSetter, staticTargets []exporter.Target) *accountDiscovery {
+	return &accountDiscovery{
+		logger:         logger,
+		configuration:  configuration,
+		clients:        clients,
+		client:         client,
+		collector:      collector,
+		staticTargets:  staticTargets,
+		accountTargets: make(map[string][]exporter.Target),
+	}
+}
+
+// getOrganizationRoleArn returns the ARN of the IAM role to assume in the account
+func getOrganizationRoleArn(account organizations.Account, roleName string) string {
+	partition := "aws"
+
+	if accountArn, err := arn.Parse(account.Arn); err == nil {
+		partition = accountArn.Partition
+	}
+
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, account.ID, roleName)
+}
+
+// discover lists accounts of the organization and updates targets of the collector
+// Accounts that can't be collected (eg. missing IAM role) are ignored until next discovery
+func (d *accountDiscovery) discover(ctx context.Context) error {
+	fetcher := organizations.NewFetcher(ctx, d.client, *d.logger, organizations.Configuration{
+		OrganizationalUnits: d.configuration.OrganizationUnits,
+		TagSelections:       d.configuration.OrganizationTagSelections,
+	})
+
+	accounts, err := fetcher.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("can't discover organization accounts: %w", err)
+	}
+
+	accountTargets := make(map[string][]exporter.Target, len(accounts))
+
+	for _, account := range accounts {
+		if targets, found := d.accountTargets[account.ID]; found {
+			accountTargets[account.ID] = targets
+
+			continue
+		}
+
+		targets, err := getTargets(d.logger, d.configuration, d.clients, []targetConfig{{
+			RoleArn:     getOrganizationRoleArn(account, d.configuration.OrganizationRoleName),
+			ExternalID:  d.configuration.OrganizationExternalID,
+			SessionName: d.configuration.AWSAssumeRoleSession,
+			Regions:     d.configuration.Regions,
+		}})
+		if err != nil {
+			d.logger.Warn("can't collect organization account", "aws_account_id", account.ID, "name", account.Name, "reason", err)
+
+			continue
+		}
+
+		d.logger.Info("discovered organization account", "aws_account_id", account.ID, "name", account.Name)
+
+		accountTargets[account.ID] = targets
+	}
+
+	d.accountTargets = accountTargets
+
+	targets := slices.Clone(d.staticTargets)
+
+	accountIDs := make([]string, 0, len(accountTargets))
+	for accountID := range accountTargets {
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	slices.Sort(accountIDs)
+
+	for _, accountID := range accountIDs {
+		targets = append(targets, accountTargets[accountID]...)
+	}
+
+	d.collector.SetTargets(targets...)
+
+	return nil
+}
+
+// Run discovers accounts every interval until the context is cancelled
+// Periodic discovery is disabled when interval is zero or negative
+func (d *accountDiscovery) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		d.logger.Info("periodic discovery of organization accounts is disabled")
+
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.discover(ctx)
+			if err != nil {
+				d.logger.Error("can't update organization accounts, keep previous accounts", "reason", err)
+			}
+		}
+	}
+}
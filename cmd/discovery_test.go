@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/qonto/prometheus-rds-exporter/internal/app/exporter"
+	mock "github.com/qonto/prometheus-rds-exporter/internal/app/organizations/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type targetRecorder struct {
+	calls int
+}
+
+func (r *targetRecorder) SetTargets(targets ...exporter.Target) {
+	r.calls++
+}
+
+func TestAccountDiscoveryRunIsDisabledWithoutInterval(t *testing.T) {
+	testCases := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{name: "zero", interval: 0},
+		{name: "negative", interval: -time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := &targetRecorder{}
+			discovery := newAccountDiscovery(slog.Default(), exporterConfig{}, awsClientsConfig{}, mock.NewOrganizationsClient(), recorder, nil)
+
+			done := make(chan struct{})
+
+			go func() {
+				discovery.Run(context.Background(), tc.interval)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("periodic discovery should be disabled")
+			}
+
+			assert.Equal(t, 0, recorder.calls, "accounts should not be discovered")
+		})
+	}
+}
@@ -2,18 +2,115 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/pi"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/exporter"
+	"github.com/qonto/prometheus-rds-exporter/internal/infra/awsapi"
 )
 
-func getAWSConfiguration(logger *slog.Logger, roleArn string, sessionName string) (aws.Config, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+// allEnabledRegions is the regions configuration value to collect all regions enabled in the AWS account
+const allEnabledRegions = "all-enabled"
+
+// awsClientsConfig configures retries and instrumentation of AWS clients
+type awsClientsConfig struct {
+	apiMetrics     *awsapi.Metrics
+	budget         *awsapi.Budget
+	defaultRetryer func() aws.Retryer
+	retryers       map[string]func() aws.Retryer // AWS service name => retryer
+}
+
+func newAWSClientsConfig(configuration exporterConfig, apiMetrics *awsapi.Metrics) (awsClientsConfig, error) {
+	defaults := awsapi.RetryConfiguration{
+		Mode:        configuration.AWSRetryMode,
+		MaxAttempts: configuration.AWSRetryMaxAttempts,
+		MaxBackoff:  configuration.AWSRetryMaxBackoff,
+	}
+
+	defaultRetryer, err := awsapi.NewRetryer(defaults)
+	if err != nil {
+		return awsClientsConfig{}, fmt.Errorf("invalid AWS retry configuration: %w", err)
+	}
+
+	for service := range configuration.AWSServiceRetries {
+		if !slices.Contains(awsapi.Services(), service) {
+			return awsClientsConfig{}, fmt.Errorf("invalid AWS retry configuration: unknown AWS service %q, must be one of %v", service, awsapi.Services())
+		}
+	}
+
+	budgets := make(map[string]awsapi.BudgetConfiguration)
+
+	for service, budgetConfiguration := range configuration.AWSAPIBudgets {
+		if !slices.Contains(awsapi.Services(), service) {
+			return awsClientsConfig{}, fmt.Errorf("invalid AWS API budget configuration: unknown AWS service %q, must be one of %v", service, awsapi.Services())
+		}
+
+		budgets[service] = awsapi.BudgetConfiguration{
+			Rate:  budgetConfiguration.Rate,
+			Burst: budgetConfiguration.Burst,
+		}
+	}
+
+	budget, err := awsapi.NewBudget(budgets)
+	if err != nil {
+		return awsClientsConfig{}, fmt.Errorf("invalid AWS API budget configuration: %w", err)
+	}
+
+	retryers := make(map[string]func() aws.Retryer)
+
+	for _, service := range awsapi.Services() {
+		serviceConfiguration := configuration.AWSServiceRetries[service]
+
+		retryer, err := awsapi.NewRetryer(awsapi.RetryConfiguration{
+			Mode:        serviceConfiguration.Mode,
+			MaxAttempts: serviceConfiguration.MaxAttempts,
+			MaxBackoff:  serviceConfiguration.MaxBackoff,
+		}.Merge(defaults))
+		if err != nil {
+			return awsClientsConfig{}, fmt.Errorf("invalid AWS retry configuration of %s: %w", service, err)
+		}
+
+		retryers[service] = retryer
+	}
+
+	return awsClientsConfig{
+		apiMetrics:     apiMetrics,
+		budget:         budget,
+		defaultRetryer: defaultRetryer,
+		retryers:       retryers,
+	}, nil
+}
+
+// retryer returns the retryer of the AWS service
+func (c awsClientsConfig) retryer(service string) aws.Retryer {
+	if retryer, found := c.retryers[service]; found {
+		return retryer()
+	}
+
+	return c.defaultRetryer()
+}
+
+func getAWSConfiguration(logger *slog.Logger, clients awsClientsConfig, roleArn string, externalID string, sessionName string) (aws.Config, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO(),
+		config.WithRetryer(clients.defaultRetryer),
+		config.WithAPIOptions([]func(*middleware.Stack) error{clients.apiMetrics.AddMiddlewares, clients.budget.AddMiddlewares}),
+	)
 	if err != nil {
 		return aws.Config{}, fmt.Errorf("can't create AWS session: %w", err)
 	}
@@ -24,6 +121,10 @@ func getAWSConfiguration(logger *slog.Logger, roleArn string, sessionName string
 		client := sts.NewFromConfig(cfg)
 		creds := stscreds.NewAssumeRoleProvider(client, roleArn, func(o *stscreds.AssumeRoleOptions) {
 			o.RoleSessionName = sessionName
+
+			if externalID != "" {
+				o.ExternalID = aws.String(externalID)
+			}
 		})
 		cfg.Credentials = aws.NewCredentialsCache(creds)
 	}
@@ -54,3 +155,182 @@ func getAWSSessionInformation(cfg aws.Config) (string, string, error) {
 
 	return *output.Account, cfg.Region, nil
 }
+
+// getAWSRegions returns the AWS regions to collect
+func getAWSRegions(cfg aws.Config, regions []string) ([]string, error) {
+	if len(regions) == 0 {
+		return []string{cfg.Region}, nil
+	}
+
+	if len(regions) > 1 || regions[0] != allEnabledRegions {
+		return regions, nil
+	}
+
+	client := ec2.NewFromConfig(cfg)
+
+	output, err := client.DescribeRegions(context.TODO(), &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("can't describe enabled regions: %w", err)
+	}
+
+	enabledRegions := make([]string, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		enabledRegions = append(enabledRegions, aws.ToString(region.RegionName))
+	}
+
+	return enabledRegions, nil
+}
+
+// newTarget creates AWS clients for the region
+func newTarget(cfg aws.Config, clients awsClientsConfig, awsAccountID string, region string, withTagClient bool) exporter.Target {
+	regionalCfg := cfg.Copy()
+	regionalCfg.Region = region
+	regionalCfg.APIOptions = append(slices.Clone(cfg.APIOptions), awsapi.WithAccountID(awsAccountID))
+
+	target := exporter.Target{
+		AWSAccountID: awsAccountID,
+		AWSRegion:    region,
+		RDSClient: rds.NewFromConfig(regionalCfg, func(o *rds.Options) {
+			o.Retryer = clients.retryer(awsapi.RDSService)
+		}),
+		EC2Client: ec2.NewFromConfig(regionalCfg, func(o *ec2.Options) {
+			o.Retryer = clients.retryer(awsapi.EC2Service)
+		}),
+		CloudWatchClient: cloudwatch.NewFromConfig(regionalCfg, func(o *cloudwatch.Options) {
+			o.Retryer = clients.retryer(awsapi.CloudWatchService)
+		}),
+		ServiceQuotasClient: servicequotas.NewFromConfig(regionalCfg, func(o *servicequotas.Options) {
+			o.Retryer = clients.retryer(awsapi.ServiceQuotasService)
+		}),
+		PerformanceInsightsClient: pi.NewFromConfig(regionalCfg, func(o *pi.Options) {
+			o.Retryer = clients.retryer(awsapi.PerformanceInsightsService)
+		}),
+		CloudWatchLogsClient: cloudwatchlogs.NewFromConfig(regionalCfg, func(o *cloudwatchlogs.Options) {
+			o.Retryer = clients.retryer(awsapi.CloudWatchLogsService)
+		}),
+	}
+
+	if withTagClient {
+		target.TagClient = resourcegroupstaggingapi.NewFromConfig(regionalCfg, func(o *resourcegroupstaggingapi.Options) {
+			o.Retryer = clients.retryer(awsapi.TagService)
+		})
+	}
+
+	return target
+}
+
+// getTargets returns AWS accounts and regions to collect
+// Targets that can't be initialized (eg. denied assume role) are logged and returned with their error, so other targets are still collected
+func getTargets(logger *slog.Logger, configuration exporterConfig, clients awsClientsConfig, targetConfigurations []targetConfig) ([]exporter.Target, error) {
+	var (
+		targets []exporter.Target
+		errs    []error
+	)
+
+	for _, targetConfiguration := range targetConfigurations {
+		configurationTargets, err := getConfigurationTargets(logger, configuration, clients, targetConfiguration)
+		if err != nil {
+			logger.Error("can't initialize AWS target, skip it", "role", targetConfiguration.RoleArn, "reason", err)
+
+			errs = append(errs, err)
+		}
+
+		targets = append(targets, configurationTargets...)
+	}
+
+	return targets, errors.Join(errs...)
+}
+
+// getConfigurationTargets returns AWS regions to collect with the target configuration
+// On error, returned targets are unavailable targets exposed as down
+func getConfigurationTargets(logger *slog.Logger, configuration exporterConfig, clients awsClientsConfig, targetConfiguration targetConfig) ([]exporter.Target, error) {
+	sessionName := targetConfiguration.SessionName
+	if sessionName == "" {
+		sessionName = configuration.AWSAssumeRoleSession
+	}
+
+	regions := targetConfiguration.Regions
+	if len(regions) == 0 {
+		regions = configuration.Regions
+	}
+
+	awsAccountID := getRoleAccountID(targetConfiguration.RoleArn)
+
+	cfg, err := getAWSConfiguration(logger, clients, targetConfiguration.RoleArn, targetConfiguration.ExternalID, sessionName)
+	if err != nil {
+		err = fmt.Errorf("can't initialize AWS configuration for %s: %w", targetConfiguration.RoleArn, err)
+
+		return unavailableTargets(awsAccountID, regions, err), err
+	}
+
+	if len(regions) == 0 {
+		regions = []string{cfg.Region}
+	}
+
+	sessionAccountID, _, err := getAWSSessionInformation(cfg)
+	if err != nil {
+		err = fmt.Errorf("can't identify AWS account for %s: %w", targetConfiguration.RoleArn, err)
+
+		return unavailableTargets(awsAccountID, regions, err), err
+	}
+
+	awsAccountID = sessionAccountID
+
+	awsRegions, err := getAWSRegions(cfg, regions)
+	if err != nil {
+		err = fmt.Errorf("can't list AWS regions of %s: %w", awsAccountID, err)
+
+		return unavailableTargets(awsAccountID, regions, err), err
+	}
+
+	targets := make([]exporter.Target, 0, len(awsRegions))
+
+	for _, region := range awsRegions {
+		logger.Debug("collect AWS target", "aws_account_id", awsAccountID, "aws_region", region)
+
+		targets = append(targets, newTarget(cfg, clients, awsAccountID, region, configuration.TagSelections != nil))
+	}
+
+	return targets, nil
+}
+
+// getRoleAccountID returns the AWS account ID of the IAM role, or an empty string for the current session
+func getRoleAccountID(roleArn string) string {
+	roleARN, err := arn.Parse(roleArn)
+	if err != nil {
+		return ""
+	}
+
+	return roleARN.AccountID
+}
+
+// unavailableTargets returns targets of the AWS regions that can't be collected because of the initialization error
+// Regions are the configured regions (eg. "all-enabled" when enabled regions can't be listed)
+func unavailableTargets(awsAccountID string, regions []string, err error) []exporter.Target {
+	if len(regions) == 0 {
+		regions = []string{""} // AWS region is unknown without AWS configuration
+	}
+
+	targets := make([]exporter.Target, 0, len(regions))
+
+	for _, region := range regions {
+		targets = append(targets, exporter.Target{
+			AWSAccountID: awsAccountID,
+			AWSRegion:    region,
+			Err:          err,
+		})
+	}
+
+	return targets
+}
+
+// hasAvailableTarget returns true if at least one target can be collected
+func hasAvailableTarget(targets []exporter.Target) bool {
+	for _, target := range targets {
+		if target.Err == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,24 +2,26 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
-	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
-
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	"github.com/aws/aws-sdk-go-v2/service/rds"
-	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	aws_organizations "github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
 	"github.com/knadh/koanf/v2"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/cloudwatch"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/exporter"
+	"github.com/qonto/prometheus-rds-exporter/internal/infra/awsapi"
 	"github.com/qonto/prometheus-rds-exporter/internal/infra/build"
 	"github.com/qonto/prometheus-rds-exporter/internal/infra/http"
 	"github.com/qonto/prometheus-rds-exporter/internal/infra/logger"
@@ -39,26 +41,161 @@ var (
 )
 
 type exporterConfig struct {
-	Debug                     bool                `koanf:"debug"`
-	LogFormat                 string              `koanf:"log-format"`
-	TLSCertPath               string              `koanf:"tls-cert-path"`
-	TLSKeyPath                string              `koanf:"tls-key-path"`
-	MetricPath                string              `koanf:"metrics-path"`
-	ListenAddress             string              `koanf:"listen-address"`
-	AWSAssumeRoleSession      string              `koanf:"aws-assume-role-session"`
-	AWSAssumeRoleArn          string              `koanf:"aws-assume-role-arn"`
-	CollectInstanceMetrics    bool                `koanf:"collect-instance-metrics"`
-	CollectInstanceTags       bool                `koanf:"collect-instance-tags"`
-	CollectInstanceTypes      bool                `koanf:"collect-instance-types"`
-	CollectLogsSize           bool                `koanf:"collect-logs-size"`
-	CollectServerlessLogsSize bool                `koanf:"collect-serverless-logs-size"`
-	CollectMaintenances       bool                `koanf:"collect-maintenances"`
-	CollectClusterMetrics     bool                `koanf:"collect-cluster-metrics"`
-	CollectQuotas             bool                `koanf:"collect-quotas"`
-	CollectUsages             bool                `koanf:"collect-usages"`
-	CollectEngineSupport      bool                `koanf:"collect-engine-support"`
-	OTELTracesEnabled         bool                `koanf:"enable-otel-traces"`
-	TagSelections             map[string][]string `koanf:"tag-selections"`
+	Debug                              bool                     `koanf:"debug"`
+	LogFormat                          string                   `koanf:"log-format"`
+	TLSCertPath                        string                   `koanf:"tls-cert-path"`
+	TLSKeyPath                         string                   `koanf:"tls-key-path"`
+	MetricPath                         string                   `koanf:"metrics-path"`
+	ListenAddress                      string                   `koanf:"listen-address"`
+	AWSAssumeRoleSession               string                   `koanf:"aws-assume-role-session"`
+	AWSAssumeRoleArn                   string                   `koanf:"aws-assume-role-arn"`
+	AWSAssumeRoleExternalID            string                   `koanf:"aws-assume-role-external-id"`
+	CollectInstanceMetrics             bool                     `koanf:"collect-instance-metrics"`
+	CollectInstanceTags                bool                     `koanf:"collect-instance-tags"`
+	CollectInstanceTypes               bool                     `koanf:"collect-instance-types"`
+	CollectLogsSize                    bool                     `koanf:"collect-logs-size"`
+	CollectServerlessLogsSize          bool                     `koanf:"collect-serverless-logs-size"`
+	CollectMaintenances                bool                     `koanf:"collect-maintenances"`
+	CollectClusterMetrics              bool                     `koanf:"collect-cluster-metrics"`
+	CollectQuotas                      bool                     `koanf:"collect-quotas"`
+	CollectUsages                      bool                     `koanf:"collect-usages"`
+	CollectEngineSupport               bool                     `koanf:"collect-engine-support"`
+	CollectProxies                     bool                     `koanf:"collect-proxies"`
+	CollectPerformanceInsights         bool                     `koanf:"collect-performance-insights"`
+	CollectOSMetrics                   bool                     `koanf:"collect-os-metrics"`
+	PerformanceInsightsTopN            int                      `koanf:"performance-insights-top-n"`
+	QuotaCodes                         []string                 `koanf:"quota-codes"`
+	OTELTracesEnabled                  bool                     `koanf:"enable-otel-traces"`
+	TagSelections                      map[string][]string      `koanf:"tag-selections"`
+	RefreshInterval                    time.Duration            `koanf:"refresh-interval"`
+	RefreshMinInterval                 time.Duration            `koanf:"refresh-min-interval"`
+	RDSRefreshInterval                 time.Duration            `koanf:"rds-refresh-interval"`
+	CloudWatchRefreshInterval          time.Duration            `koanf:"cloudwatch-refresh-interval"`
+	UsageRefreshInterval               time.Duration            `koanf:"usage-refresh-interval"`
+	EC2RefreshInterval                 time.Duration            `koanf:"ec2-refresh-interval"`
+	QuotasRefreshInterval              time.Duration            `koanf:"quotas-refresh-interval"`
+	EngineSupportRefreshInterval       time.Duration            `koanf:"engine-support-refresh-interval"`
+	PerformanceInsightsRefreshInterval time.Duration            `koanf:"performance-insights-refresh-interval"`
+	OSMetricsRefreshInterval           time.Duration            `koanf:"os-metrics-refresh-interval"`
+	ScrapeTimeout                      time.Duration            `koanf:"scrape-timeout"`
+	Regions                            []string                 `koanf:"regions"`
+	Targets                            []targetConfig           `koanf:"targets"`
+	DiscoverOrganizationAccounts       bool                     `koanf:"discover-organization-accounts"`
+	OrganizationRoleName               string                   `koanf:"organization-role-name"`
+	OrganizationExternalID             string                   `koanf:"organization-external-id"`
+	OrganizationUnits                  []string                 `koanf:"organization-units"`
+	OrganizationTagSelections          map[string][]string      `koanf:"organization-tag-selections"`
+	OrganizationDiscoveryInterval      time.Duration            `koanf:"organization-discovery-interval"`
+	AWSRetryMode                       string                   `koanf:"aws-retry-mode"`
+	AWSRetryMaxAttempts                int                      `koanf:"aws-retry-max-attempts"`
+	AWSRetryMaxBackoff                 time.Duration            `koanf:"aws-retry-max-backoff"`
+	AWSServiceRetries                  map[string]retryConfig   `koanf:"aws-service-retries"`
+	AWSAPIBudgets                      map[string]budgetConfig  `koanf:"aws-api-budgets"`
+	CloudWatchMetrics                  []cloudwatchMetricConfig `koanf:"cloudwatch-metrics"`
+	CloudWatchMetricPacks              []string                 `koanf:"cloudwatch-metric-packs"`
+	CloudWatchClusterMetrics           []cloudwatchMetricConfig `koanf:"cloudwatch-cluster-metrics"`
+	CloudWatchTimestamps               bool                     `koanf:"cloudwatch-timestamps"`
+}
+
+// cloudwatchMetricConfig is a CloudWatch metric collected for each instance or each Aurora cluster
+type cloudwatchMetricConfig struct {
+	CloudWatchName  string        `koanf:"cloudwatch-name"`
+	Statistic       string        `koanf:"statistic"`
+	Statistics      []string      `koanf:"statistics"`
+	Period          time.Duration `koanf:"period"`
+	Name            string        `koanf:"name"`
+	Help            string        `koanf:"help"`
+	Scale           float64       `koanf:"scale"`
+	Engines         []string      `koanf:"engines"`
+	InstanceClasses []string      `koanf:"instance-classes"`
+	StorageTypes    []string      `koanf:"storage-types"`
+	BurstableEBS    bool          `koanf:"burstable-ebs"`
+}
+
+// getCloudWatchMetricDefinitions returns configured CloudWatch metrics of instances and Aurora clusters
+// Empty lists are returned as nil to collect default metrics
+func getCloudWatchMetricDefinitions(configuration exporterConfig) ([]cloudwatch.MetricDefinition, []cloudwatch.MetricDefinition, error) {
+	instanceDefinitions := toCloudWatchMetricDefinitions(configuration.CloudWatchMetrics)
+	clusterDefinitions := toCloudWatchMetricDefinitions(configuration.CloudWatchClusterMetrics)
+
+	packDefinitions, err := cloudwatch.MetricPackDefinitions(configuration.CloudWatchMetricPacks)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CloudWatch metric packs configuration: %w", err)
+	}
+
+	// Metric packs are collected in addition to configured or default metrics
+	if len(packDefinitions) > 0 {
+		if instanceDefinitions == nil {
+			instanceDefinitions = cloudwatch.DefaultMetricDefinitions()
+		}
+
+		instanceDefinitions = append(instanceDefinitions, packDefinitions...)
+	}
+
+	// Prometheus metric names must be unique across instance and cluster metrics, including default metrics
+	effectiveInstanceDefinitions := instanceDefinitions
+	if effectiveInstanceDefinitions == nil {
+		effectiveInstanceDefinitions = cloudwatch.DefaultMetricDefinitions()
+	}
+
+	effectiveClusterDefinitions := clusterDefinitions
+	if effectiveClusterDefinitions == nil {
+		effectiveClusterDefinitions = cloudwatch.DefaultClusterMetricDefinitions()
+	}
+
+	err = cloudwatch.ValidateMetricDefinitions(slices.Concat(effectiveInstanceDefinitions, effectiveClusterDefinitions))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CloudWatch metrics configuration: %w", err)
+	}
+
+	return instanceDefinitions, clusterDefinitions, nil
+}
+
+func toCloudWatchMetricDefinitions(configurations []cloudwatchMetricConfig) []cloudwatch.MetricDefinition {
+	if len(configurations) == 0 {
+		return nil
+	}
+
+	definitions := make([]cloudwatch.MetricDefinition, 0, len(configurations))
+
+	for _, configuration := range configurations {
+		definitions = append(definitions, cloudwatch.MetricDefinition{
+			CloudWatchName:  configuration.CloudWatchName,
+			Statistic:       configuration.Statistic,
+			Statistics:      configuration.Statistics,
+			Period:          configuration.Period,
+			Name:            configuration.Name,
+			Help:            configuration.Help,
+			Scale:           configuration.Scale,
+			Engines:         configuration.Engines,
+			InstanceClasses: configuration.InstanceClasses,
+			StorageTypes:    configuration.StorageTypes,
+			BurstableEBS:    configuration.BurstableEBS,
+		})
+	}
+
+	return definitions
+}
+
+// budgetConfig limits AWS API calls of an AWS service
+type budgetConfig struct {
+	Rate  float64 `koanf:"rate"`
+	Burst int     `koanf:"burst"`
+}
+
+// retryConfig overrides the AWS retry configuration of an AWS service
+type retryConfig struct {
+	Mode        string        `koanf:"mode"`
+	MaxAttempts int           `koanf:"max-attempts"`
+	MaxBackoff  time.Duration `koanf:"max-backoff"`
+}
+
+// targetConfig is an AWS account to collect through an assumed role
+type targetConfig struct {
+	RoleArn     string   `koanf:"role-arn"`
+	ExternalID  string   `koanf:"external-id"`
+	SessionName string   `koanf:"session-name"`
+	Regions     []string `koanf:"regions"`
 }
 
 func run(configuration exporterConfig) {
@@ -70,47 +207,101 @@ func run(configuration exporterConfig) {
 
 	logger.Debug(fmt.Sprintf("Config: %+v\n", configuration))
 
-	cfg, err := getAWSConfiguration(logger, configuration.AWSAssumeRoleArn, configuration.AWSAssumeRoleSession)
+	// Stop signals of the web server also cancel AWS API calls in progress, so they don't delay the shutdown
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT)
+	defer cancel()
+
+	// Without static targets, collect current AWS session unless accounts are discovered in AWS organization
+	targetConfigurations := configuration.Targets
+	if len(targetConfigurations) == 0 && !configuration.DiscoverOrganizationAccounts {
+		targetConfigurations = []targetConfig{{
+			RoleArn:     configuration.AWSAssumeRoleArn,
+			ExternalID:  configuration.AWSAssumeRoleExternalID,
+			SessionName: configuration.AWSAssumeRoleSession,
+			Regions:     configuration.Regions,
+		}}
+	}
+
+	apiMetrics := awsapi.NewMetrics()
+
+	clients, err := newAWSClientsConfig(configuration, apiMetrics)
 	if err != nil {
-		logger.Error("can't initialize AWS configuration", "reason", err)
-		os.Exit(awsErrorExitCode)
+		logger.Error("can't initialize AWS clients", "reason", err)
+		os.Exit(configErrorExitCode)
 	}
 
-	awsAccountID, awsRegion, err := getAWSSessionInformation(cfg)
+	cloudwatchMetrics, cloudwatchClusterMetrics, err := getCloudWatchMetricDefinitions(configuration)
 	if err != nil {
-		logger.Error("can't identify AWS account and/or region", "reason", err)
+		logger.Error("can't initialize CloudWatch metrics", "reason", err)
+		os.Exit(configErrorExitCode)
+	}
+
+	// Targets that can't be initialized are exposed as down, the exporter exits only when no target can be collected
+	targets, err := getTargets(logger, configuration, clients, targetConfigurations)
+	if err != nil && !hasAvailableTarget(targets) && !configuration.DiscoverOrganizationAccounts {
+		logger.Error("can't initialize any AWS target", "reason", err)
 		os.Exit(awsErrorExitCode)
 	}
 
-	rdsClient := rds.NewFromConfig(cfg)
+	collectorConfiguration := exporter.Configuration{
+		CollectInstanceMetrics:             configuration.CollectInstanceMetrics,
+		CollectInstanceTypes:               configuration.CollectInstanceTypes,
+		CollectInstanceTags:                configuration.CollectInstanceTags,
+		CollectLogsSize:                    configuration.CollectLogsSize,
+		CollectServerlessLogsSize:          configuration.CollectServerlessLogsSize,
+		CollectMaintenances:                configuration.CollectMaintenances,
+		CollectClusterMetrics:              configuration.CollectClusterMetrics,
+		CollectQuotas:                      configuration.CollectQuotas,
+		CollectUsages:                      configuration.CollectUsages,
+		CollectEngineSupport:               configuration.CollectEngineSupport,
+		CollectProxies:                     configuration.CollectProxies,
+		CollectPerformanceInsights:         configuration.CollectPerformanceInsights,
+		CollectOSMetrics:                   configuration.CollectOSMetrics,
+		PerformanceInsightsTopN:            configuration.PerformanceInsightsTopN,
+		QuotaCodes:                         configuration.QuotaCodes,
+		TagSelections:                      configuration.TagSelections,
+		RefreshInterval:                    configuration.RefreshInterval,
+		RDSRefreshInterval:                 configuration.RDSRefreshInterval,
+		CloudWatchRefreshInterval:          configuration.CloudWatchRefreshInterval,
+		UsageRefreshInterval:               configuration.UsageRefreshInterval,
+		EC2RefreshInterval:                 configuration.EC2RefreshInterval,
+		ServiceQuotasRefreshInterval:       configuration.QuotasRefreshInterval,
+		EngineSupportRefreshInterval:       configuration.EngineSupportRefreshInterval,
+		PerformanceInsightsRefreshInterval: configuration.PerformanceInsightsRefreshInterval,
+		OSMetricsRefreshInterval:           configuration.OSMetricsRefreshInterval,
+		ScrapeTimeout:                      configuration.ScrapeTimeout,
+		APIBudget:                          clients.budget,
+		CloudWatchMetrics:                  cloudwatchMetrics,
+		CloudWatchClusterMetrics:           cloudwatchClusterMetrics,
+		CloudWatchTimestamps:               configuration.CloudWatchTimestamps,
+	}
 
-	var tagClient *resourcegroupstaggingapi.Client
+	collector := exporter.NewMultiCollector(ctx, *logger, collectorConfiguration, targets...)
 
-	if configuration.TagSelections != nil {
-		tagClient = resourcegroupstaggingapi.NewFromConfig(cfg)
-	}
+	if configuration.DiscoverOrganizationAccounts {
+		cfg, err := getAWSConfiguration(logger, clients, configuration.AWSAssumeRoleArn, configuration.AWSAssumeRoleExternalID, configuration.AWSAssumeRoleSession)
+		if err != nil {
+			logger.Error("can't initialize AWS configuration", "reason", err)
+			os.Exit(awsErrorExitCode)
+		}
 
-	ec2Client := ec2.NewFromConfig(cfg)
-	cloudWatchClient := cloudwatch.NewFromConfig(cfg)
-	servicequotasClient := servicequotas.NewFromConfig(cfg)
+		organizationsClient := aws_organizations.NewFromConfig(cfg, func(o *aws_organizations.Options) {
+			o.Retryer = clients.retryer(awsapi.OrganizationsService)
+		})
 
-	collectorConfiguration := exporter.Configuration{
-		CollectInstanceMetrics:    configuration.CollectInstanceMetrics,
-		CollectInstanceTypes:      configuration.CollectInstanceTypes,
-		CollectInstanceTags:       configuration.CollectInstanceTags,
-		CollectLogsSize:           configuration.CollectLogsSize,
-		CollectServerlessLogsSize: configuration.CollectServerlessLogsSize,
-		CollectMaintenances:       configuration.CollectMaintenances,
-		CollectClusterMetrics:     configuration.CollectClusterMetrics,
-		CollectQuotas:             configuration.CollectQuotas,
-		CollectUsages:             configuration.CollectUsages,
-		CollectEngineSupport:      configuration.CollectEngineSupport,
-		TagSelections:             configuration.TagSelections,
-	}
+		discovery := newAccountDiscovery(logger, configuration, clients, organizationsClient, collector, targets)
 
-	collector := exporter.NewCollector(*logger, collectorConfiguration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, tagClient)
+		err = discovery.discover(ctx)
+		if err != nil {
+			logger.Error("can't discover organization accounts", "reason", err)
+			os.Exit(awsErrorExitCode)
+		}
+
+		go discovery.Run(ctx, configuration.OrganizationDiscoveryInterval)
+	}
 
 	prometheus.MustRegister(collector)
+	prometheus.MustRegister(apiMetrics)
 
 	serverConfiguration := http.Config{
 		ListenAddress:     configuration.ListenAddress,
@@ -120,6 +311,17 @@ func run(configuration exporterConfig) {
 		OTELTracesEnabled: configuration.OTELTracesEnabled,
 	}
 
+	// Refresh metrics in background instead of querying AWS APIs on each scrape
+	if configuration.RefreshInterval > 0 {
+		logger.Info("enable background refresh", "interval", configuration.RefreshInterval)
+
+		go collector.Run(ctx)
+
+		serverConfiguration.Refresher = collector
+		serverConfiguration.RefreshTimeout = configuration.ScrapeTimeout
+		serverConfiguration.RefreshMinInterval = configuration.RefreshMinInterval
+	}
+
 	server := http.New(*logger, serverConfiguration)
 
 	err = server.Start()
@@ -165,7 +367,14 @@ func NewRootCommand() (*cobra.Command, error) {
 	cmd.Flags().StringP("tls-key-path", "", "", "Path to private key for TLS")
 	cmd.Flags().StringP("listen-address", "", ":9043", "Address to listen on for web interface")
 	cmd.Flags().StringP("aws-assume-role-arn", "", "", "AWS IAM ARN role to assume to fetch metrics")
+	cmd.Flags().StringP("aws-assume-role-external-id", "", "", "AWS assume role external ID")
 	cmd.Flags().StringP("aws-assume-role-session", "", "prometheus-rds-exporter", "AWS assume role session name")
+	cmd.Flags().StringSliceP("regions", "", []string{}, "AWS regions to collect (default is the current AWS region, \"all-enabled\" collects all regions enabled in the AWS account)")
+	cmd.Flags().BoolP("discover-organization-accounts", "", false, "Discover AWS accounts to collect in AWS organization")
+	cmd.Flags().StringP("organization-role-name", "", "prometheus-rds-exporter", "AWS IAM role name to assume in discovered AWS accounts")
+	cmd.Flags().StringP("organization-external-id", "", "", "AWS assume role external ID of discovered AWS accounts")
+	cmd.Flags().StringSliceP("organization-units", "", []string{}, "AWS organizational units to discover AWS accounts in (default is the whole organization)")
+	cmd.Flags().DurationP("organization-discovery-interval", "", time.Hour, "Interval between discoveries of AWS accounts in AWS organization")
 	cmd.Flags().BoolP("collect-instance-tags", "", true, "Collect AWS RDS tags")
 	cmd.Flags().BoolP("collect-instance-types", "", true, "Collect AWS instance types")
 	cmd.Flags().BoolP("collect-instance-metrics", "", true, "Collect AWS instance metrics")
@@ -174,8 +383,29 @@ func NewRootCommand() (*cobra.Command, error) {
 	cmd.Flags().BoolP("collect-maintenances", "", true, "Collect AWS instances maintenances")
 	cmd.Flags().BoolP("collect-cluster-metrics", "", true, "Collect AWS RDS cluster metrics")
 	cmd.Flags().BoolP("collect-quotas", "", true, "Collect AWS RDS quotas")
+	cmd.Flags().StringSliceP("quota-codes", "", []string{}, "AWS RDS quota codes exported by rds_quota_value (default is all AWS RDS quotas)")
 	cmd.Flags().BoolP("collect-engine-support", "", true, "Collect engine version support lifecycle information")
+	cmd.Flags().BoolP("collect-proxies", "", false, "Collect RDS proxies, their targets health and CloudWatch metrics")
+	cmd.Flags().BoolP("collect-performance-insights", "", false, "Collect Performance Insights top wait events and SQL statements of instances")
+	cmd.Flags().IntP("performance-insights-top-n", "", 10, "Number of top wait events and SQL statements collected for each instance (maximum 25)")
+	cmd.Flags().BoolP("collect-os-metrics", "", false, "Collect Enhanced Monitoring OS metrics of instances from CloudWatch Logs")
 	cmd.Flags().BoolP("collect-usages", "", true, "Collect AWS RDS usages")
+	cmd.Flags().StringSliceP("cloudwatch-metric-packs", "", []string{}, fmt.Sprintf("CloudWatch metric packs collected in addition to instance metrics (%s)", strings.Join(cloudwatch.MetricPacks(), ", ")))
+	cmd.Flags().BoolP("cloudwatch-timestamps", "", false, "Attach CloudWatch datapoint timestamps to AWS Cloudwatch metrics")
+	cmd.Flags().DurationP("refresh-interval", "", 0, "Interval between background refreshes of AWS metrics (0 queries AWS APIs on each scrape)")
+	cmd.Flags().DurationP("refresh-min-interval", "", time.Minute, "Minimum interval between refreshes forced with the refresh endpoint")
+	cmd.Flags().DurationP("rds-refresh-interval", "", 0, "Minimum interval between fetches of AWS RDS instances and clusters")
+	cmd.Flags().DurationP("cloudwatch-refresh-interval", "", 0, "Minimum interval between fetches of AWS Cloudwatch instance metrics")
+	cmd.Flags().DurationP("usage-refresh-interval", "", 0, "Minimum interval between fetches of AWS RDS usages")
+	cmd.Flags().DurationP("ec2-refresh-interval", "", 24*time.Hour, "Minimum interval between fetches of AWS instance types information")
+	cmd.Flags().DurationP("quotas-refresh-interval", "", time.Hour, "Minimum interval between fetches of AWS RDS quotas")
+	cmd.Flags().DurationP("engine-support-refresh-interval", "", 24*time.Hour, "Minimum interval between fetches of engine version support lifecycle information")
+	cmd.Flags().DurationP("performance-insights-refresh-interval", "", 0, "Minimum interval between fetches of Performance Insights metrics")
+	cmd.Flags().DurationP("os-metrics-refresh-interval", "", 0, "Minimum interval between fetches of Enhanced Monitoring OS metrics")
+	cmd.Flags().StringP("aws-retry-mode", "", "standard", "AWS SDK retry mode (standard or adaptive)")
+	cmd.Flags().IntP("aws-retry-max-attempts", "", 3, "Maximum number of attempts of AWS API calls, including the initial call")
+	cmd.Flags().DurationP("aws-retry-max-backoff", "", 20*time.Second, "Maximum backoff delay between attempts of AWS API calls")
+	cmd.Flags().DurationP("scrape-timeout", "", 0, "Maximum duration of a collection of AWS APIs, outstanding AWS API calls are cancelled after this duration (0 disables the timeout)")
 
 	return cmd, nil
 }
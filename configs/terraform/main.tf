@@ -58,6 +58,35 @@ data "aws_iam_policy_document" "prometheus-rds-exporter" {
     resources = ["*"]
   }
 
+  statement {
+    sid    = "AllowProxyDescriptions"
+    effect = "Allow"
+    actions = [
+      "rds:DescribeDBProxies",
+      "rds:DescribeDBProxyTargetGroups",
+      "rds:DescribeDBProxyTargets",
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "AllowPerformanceInsightsMetrics"
+    effect = "Allow"
+    actions = [
+      "pi:GetResourceMetrics",
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "AllowEnhancedMonitoringLogs"
+    effect = "Allow"
+    actions = [
+      "logs:GetLogEvents",
+    ]
+    resources = ["arn:aws:logs:*:*:log-group:RDSOSMetrics:log-stream:*"]
+  }
+
   statement {
     sid    = "AllowGettingCloudWatchMetrics"
     effect = "Allow"
@@ -71,7 +100,8 @@ data "aws_iam_policy_document" "prometheus-rds-exporter" {
     sid    = "AllowQuotaDescriptions"
     effect = "Allow"
     actions = [
-      "servicequotas:GetServiceQuota",
+      "servicequotas:ListServiceQuotas",
+      "servicequotas:ListAWSDefaultServiceQuotas",
     ]
     resources = ["*"]
   }
@@ -81,6 +111,7 @@ data "aws_iam_policy_document" "prometheus-rds-exporter" {
     effect = "Allow"
     actions = [
       "ec2:DescribeInstanceTypes",
+      "ec2:DescribeRegions",
     ]
     resources = ["*"]
   }
@@ -93,4 +124,16 @@ data "aws_iam_policy_document" "prometheus-rds-exporter" {
     ]
     resources = ["*"]
   }
+
+  statement {
+    sid    = "AllowOrganizationAccountsDiscovery"
+    effect = "Allow"
+    actions = [
+      "organizations:ListAccounts",
+      "organizations:ListAccountsForParent",
+      "organizations:ListOrganizationalUnitsForParent",
+      "organizations:ListTagsForResource",
+    ]
+    resources = ["*"]
+  }
 }
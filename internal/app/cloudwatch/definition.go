@@ -0,0 +1,297 @@
+package cloudwatch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultStatistic = "Average"
+
+// Metric packs are sets of CloudWatch metrics collected in addition to instance metrics
+const (
+	AuroraMetricPack       = "aurora"        // Aurora replication, buffer cache and latency metrics
+	BurstBalanceMetricPack = "burst-balance" // gp2, CPU credits and EBS burst balances
+	DiskLatencyMetricPack  = "disk-latency"  // Disk latencies, queue depth and checkpoint lag
+)
+
+// Units conversions of CloudWatch values
+const (
+	microsecondsToSeconds = 0.000001
+	millisecondsToSeconds = 0.001
+	percentToRatio        = 0.01
+)
+
+var (
+	errInvalidMetricDefinition = errors.New("invalid CloudWatch metric definition")
+	errUnknownMetricPack       = errors.New("unknown CloudWatch metric pack")
+
+	standardStatistics = []string{"Average", "Maximum", "Minimum", "Sum", "SampleCount"}
+
+	// percentileStatistic matches CloudWatch percentile statistics (eg. p99, p99.9)
+	percentileStatistic = regexp.MustCompile(`^p[0-9]{1,2}(\.[0-9]+)?$`)
+
+	prometheusMetricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+	// auroraEngines matches Aurora MySQL and Aurora PostgreSQL engines
+	auroraEngines = []string{"aurora"}
+
+	// postgresqlEngines matches RDS PostgreSQL and Aurora PostgreSQL engines
+	postgresqlEngines = []string{"postgres", "aurora-postgresql"}
+
+	// burstableInstanceClasses matches burstable performance instance classes using CPU credits
+	burstableInstanceClasses = []string{"db.t"}
+)
+
+// MetricDefinition defines a CloudWatch metric collected for RDS instances or clusters and its Prometheus metric
+type MetricDefinition struct {
+	CloudWatchName  string        // CloudWatch metric name in AWS/RDS namespace
+	Statistic       string        // CloudWatch statistic (default: Average)
+	Statistics      []string      // CloudWatch statistics exported with a statistic label, replaces Statistic
+	Period          time.Duration // CloudWatch period (default: 1 minute)
+	Name            string        // Prometheus metric name
+	Help            string        // Prometheus metric help
+	Scale           float64       // Factor applied to CloudWatch values to convert units (default: 1)
+	Engines         []string      // Engine prefixes of instances or clusters to query (default: all engines)
+	InstanceClasses []string      // Instance class prefixes of instances to query (default: all instance classes)
+	StorageTypes    []string      // Storage types of instances to query (default: all storage types)
+	BurstableEBS    bool          // Only query instances with burstable EBS bandwidth
+}
+
+// GetStatistics returns the CloudWatch statistics of the metric
+func (d MetricDefinition) GetStatistics() []string {
+	if len(d.Statistics) > 0 {
+		return d.Statistics
+	}
+
+	if d.Statistic == "" {
+		return []string{defaultStatistic}
+	}
+
+	return []string{d.Statistic}
+}
+
+// HasStatisticLabel returns true if the Prometheus metric has a statistic label
+func (d MetricDefinition) HasStatisticLabel() bool {
+	return len(d.Statistics) > 0
+}
+
+// StatisticLabel returns the statistic label value of a CloudWatch statistic
+func StatisticLabel(statistic string) string {
+	return strings.ToLower(statistic)
+}
+
+// GetPeriod returns the CloudWatch period of the metric in seconds
+func (d MetricDefinition) GetPeriod() int32 {
+	if d.Period == 0 {
+		return Minute
+	}
+
+	return int32(d.Period.Seconds())
+}
+
+// Matches returns true if the metric is collected for the instance or cluster
+func (d MetricDefinition) Matches(resource Resource) bool {
+	if d.BurstableEBS && !resource.BurstableEBS {
+		return false
+	}
+
+	if len(d.StorageTypes) > 0 && !slices.Contains(d.StorageTypes, resource.StorageType) {
+		return false
+	}
+
+	return matchesPrefix(d.Engines, resource.Engine) && matchesPrefix(d.InstanceClasses, resource.InstanceClass)
+}
+
+// matchesPrefix returns true if value starts with one of the prefixes, or if there is no prefix
+func matchesPrefix(prefixes []string, value string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Convert returns the CloudWatch value converted to the Prometheus metric unit
+func (d MetricDefinition) Convert(value float64) float64 {
+	if d.Scale == 0 {
+		return value
+	}
+
+	return value * d.Scale
+}
+
+// ValidateMetricDefinitions returns an error if a definition can't be queried or exported
+func ValidateMetricDefinitions(definitions []MetricDefinition) error {
+	names := make(map[string]bool, len(definitions))
+
+	for _, definition := range definitions {
+		if definition.CloudWatchName == "" {
+			return fmt.Errorf("%w: missing CloudWatch metric name of %s", errInvalidMetricDefinition, definition.Name)
+		}
+
+		if !prometheusMetricName.MatchString(definition.Name) {
+			return fmt.Errorf("%w: invalid Prometheus metric name %q of %s", errInvalidMetricDefinition, definition.Name, definition.CloudWatchName)
+		}
+
+		if names[definition.Name] {
+			return fmt.Errorf("%w: duplicate Prometheus metric name %q", errInvalidMetricDefinition, definition.Name)
+		}
+
+		names[definition.Name] = true
+
+		if definition.Statistic != "" && len(definition.Statistics) > 0 {
+			return fmt.Errorf("%w: statistic and statistics of %s are mutually exclusive", errInvalidMetricDefinition, definition.CloudWatchName)
+		}
+
+		statistics := make(map[string]bool, len(definition.Statistics))
+
+		for _, statistic := range definition.GetStatistics() {
+			if !slices.Contains(standardStatistics, statistic) && !percentileStatistic.MatchString(statistic) {
+				return fmt.Errorf("%w: invalid statistic %q of %s, must be one of %v or a percentile (eg. p99)", errInvalidMetricDefinition, statistic, definition.CloudWatchName, standardStatistics)
+			}
+
+			if statistics[statistic] {
+				return fmt.Errorf("%w: duplicate statistic %q of %s", errInvalidMetricDefinition, statistic, definition.CloudWatchName)
+			}
+
+			statistics[statistic] = true
+		}
+
+		if definition.Period < 0 || definition.Period%time.Minute != 0 {
+			return fmt.Errorf("%w: invalid period %s of %s, must be a multiple of 1m", errInvalidMetricDefinition, definition.Period, definition.CloudWatchName)
+		}
+	}
+
+	return nil
+}
+
+// DefaultMetricDefinitions returns CloudWatch metrics collected when no metrics are configured
+// Additional metrics are enabled with metric packs
+func DefaultMetricDefinitions() []MetricDefinition {
+	return []MetricDefinition{
+		{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average", Help: "Instance CPU used"},
+		{CloudWatchName: "DBLoad", Name: "rds_dbload_average", Help: "Number of active sessions for the DB engine"},
+		{CloudWatchName: "DBLoadCPU", Name: "rds_dbload_cpu_average", Help: "Number of active sessions where the wait event type is CPU"},
+		{CloudWatchName: "DBLoadNonCPU", Name: "rds_dbload_noncpu_average", Help: "Number of active sessions where the wait event type is not CPU"},
+		{CloudWatchName: "DatabaseConnections", Name: "rds_database_connections_average", Help: "The number of client network connections to the database instance"},
+		{CloudWatchName: "FreeStorageSpace", Name: "rds_free_storage_bytes", Help: "Free storage on the instance"},
+		{CloudWatchName: "FreeableMemory", Name: "rds_freeable_memory_bytes", Help: "Amount of available random access memory. For MariaDB, MySQL, Oracle, and PostgreSQL DB instances, this metric reports the value of the MemAvailable field of /proc/meminfo"},
+		{CloudWatchName: "MaximumUsedTransactionIDs", Name: "rds_maximum_used_transaction_ids_average", Help: "Maximum transaction IDs that have been used. Applies to only PostgreSQL"},
+		{CloudWatchName: "NetworkReceiveThroughput", Name: "rds_network_receive_throughput_bytes", Help: "Average number of bytes received per second from the network"},
+		{CloudWatchName: "NetworkTransmitThroughput", Name: "rds_network_transmit_throughput_bytes", Help: "Average number of bytes transmitted per second to the network"},
+		{CloudWatchName: "ReadIOPS", Name: "rds_read_iops_average", Help: "Average number of disk read I/O operations per second"},
+		{CloudWatchName: "ReadThroughput", Name: "rds_read_throughput_bytes", Help: "Average number of bytes read from disk per second"},
+		{CloudWatchName: "ReplicaLag", Name: "rds_replica_lag_seconds", Help: "For read replica configurations, the amount of time a read replica DB instance lags behind the source DB instance. Applies to MariaDB, Microsoft SQL Server, MySQL, Oracle, and PostgreSQL read replicas"},
+		{CloudWatchName: "ReplicationSlotDiskUsage", Name: "rds_replication_slot_disk_usage_bytes", Help: "Disk space used by replication slot files. Applies to PostgreSQL"},
+		{CloudWatchName: "SwapUsage", Name: "rds_swap_usage_bytes", Help: "Amount of swap space used on the DB instance. This metric is not available for SQL Server"},
+		{CloudWatchName: "ServerlessDatabaseCapacity", Name: "rds_serverless_instance_acu_average", Help: "Current ACU of the Aurora Serverless instance"},
+		{CloudWatchName: "StorageNetworkReceiveThroughput", Name: "rds_storage_network_receive_throughput_bytes", Help: "Average number of bytes received per second from the Aurora storage subsystem (Aurora only)"},
+		{CloudWatchName: "StorageNetworkTransmitThroughput", Name: "rds_storage_network_transmit_throughput_bytes", Help: "Average number of bytes transmitted per second to the Aurora storage subsystem (Aurora only)"},
+		{CloudWatchName: "TransactionLogsDiskUsage", Name: "rds_transaction_logs_disk_usage_bytes", Help: "Disk space used by transaction logs (only on PostgreSQL)"},
+		{CloudWatchName: "WriteIOPS", Name: "rds_write_iops_average", Help: "Average number of disk write I/O operations per second"},
+		{CloudWatchName: "WriteThroughput", Name: "rds_write_throughput_bytes", Help: "Average number of bytes written to disk per second"},
+	}
+}
+
+// MetricPackDefinitions returns CloudWatch metrics of metric packs, collected in addition to instance metrics
+func MetricPackDefinitions(packs []string) ([]MetricDefinition, error) {
+	var definitions []MetricDefinition
+
+	for _, pack := range packs {
+		switch pack {
+		case AuroraMetricPack:
+			definitions = append(definitions, auroraMetricDefinitions()...)
+		case BurstBalanceMetricPack:
+			definitions = append(definitions, burstBalanceMetricDefinitions()...)
+		case DiskLatencyMetricPack:
+			definitions = append(definitions, diskLatencyMetricDefinitions()...)
+		default:
+			return nil, fmt.Errorf("%w: %q, must be one of %v", errUnknownMetricPack, pack, MetricPacks())
+		}
+	}
+
+	return definitions, nil
+}
+
+// MetricPacks returns names of metric packs
+func MetricPacks() []string {
+	return []string{AuroraMetricPack, BurstBalanceMetricPack, DiskLatencyMetricPack}
+}
+
+// auroraMetricDefinitions returns Aurora instance metrics, only queried for Aurora instances
+func auroraMetricDefinitions() []MetricDefinition {
+	return []MetricDefinition{
+		{CloudWatchName: "AuroraBinlogReplicaLag", Engines: auroraEngines, Name: "rds_aurora_binlog_replica_lag_seconds", Help: "Amount of time a binary log replica DB cluster running on Aurora MySQL lags behind the binary log replication source (Aurora MySQL only)"},
+		{CloudWatchName: "AuroraReplicaLag", Engines: auroraEngines, Scale: millisecondsToSeconds, Name: "rds_aurora_replica_lag_seconds", Help: "Amount of lag when replicating updates from the primary instance of the Aurora cluster (Aurora only)"},
+		{CloudWatchName: "AuroraReplicaLagMaximum", Engines: auroraEngines, Scale: millisecondsToSeconds, Name: "rds_aurora_replica_lag_maximum_seconds", Help: "Maximum amount of lag between the primary instance and each Aurora replica of the Aurora cluster (Aurora only)"},
+		{CloudWatchName: "BufferCacheHitRatio", Engines: auroraEngines, Scale: percentToRatio, Name: "rds_aurora_buffer_cache_hit_ratio", Help: "Ratio of requests that are served by the buffer cache (Aurora only)"},
+		{CloudWatchName: "CommitLatency", Engines: auroraEngines, Scale: millisecondsToSeconds, Name: "rds_aurora_commit_latency_seconds", Help: "Average duration of commit operations (Aurora only)"},
+		{CloudWatchName: "Deadlocks", Engines: auroraEngines, Name: "rds_aurora_deadlocks_per_second_average", Help: "Average number of deadlocks in the database per second (Aurora only)"},
+		{CloudWatchName: "DMLLatency", Engines: auroraEngines, Scale: millisecondsToSeconds, Name: "rds_aurora_dml_latency_seconds", Help: "Average duration of inserts, updates, and deletes (Aurora MySQL only)"},
+		{CloudWatchName: "RDSToAuroraPostgreSQLReplicaLag", Engines: auroraEngines, Name: "rds_to_aurora_postgresql_replica_lag_seconds", Help: "Amount of lag when replicating updates from the primary RDS PostgreSQL instance to other nodes in the cluster (Aurora PostgreSQL only)"},
+	}
+}
+
+// burstBalanceMetricDefinitions returns burst balance metrics, only queried for instances using burst credits
+func burstBalanceMetricDefinitions() []MetricDefinition {
+	return []MetricDefinition{
+		{CloudWatchName: "BurstBalance", StorageTypes: []string{"gp2"}, Scale: percentToRatio, Name: "rds_burst_balance_ratio", Help: "Ratio of General Purpose SSD (gp2) burst-bucket I/O credits available (gp2 storage only)"},
+		{CloudWatchName: "CPUCreditBalance", InstanceClasses: burstableInstanceClasses, Period: 5 * time.Minute, Name: "rds_cpu_credit_balance_average", Help: "Number of earned CPU credits that the instance has accrued since it was launched or started (burstable instance classes only)"},
+		{CloudWatchName: "CPUCreditUsage", InstanceClasses: burstableInstanceClasses, Period: 5 * time.Minute, Name: "rds_cpu_credit_usage_average", Help: "Number of CPU credits spent by the instance for CPU utilization (burstable instance classes only)"},
+		{CloudWatchName: "CPUSurplusCreditBalance", InstanceClasses: burstableInstanceClasses, Period: 5 * time.Minute, Name: "rds_cpu_surplus_credit_balance_average", Help: "Number of surplus credits spent by an unlimited instance when its CPU credit balance is zero (burstable instance classes only)"},
+		{CloudWatchName: "EBSByteBalance%", BurstableEBS: true, Scale: percentToRatio, Name: "rds_ebs_byte_balance_ratio", Help: "Ratio of throughput credits remaining in the EBS burst bucket (instance classes with burstable EBS bandwidth only)"},
+		{CloudWatchName: "EBSIOBalance%", BurstableEBS: true, Scale: percentToRatio, Name: "rds_ebs_io_balance_ratio", Help: "Ratio of I/O credits remaining in the EBS burst bucket (instance classes with burstable EBS bandwidth only)"},
+	}
+}
+
+// diskLatencyMetricDefinitions returns disk latency, queue depth and checkpoint lag metrics
+func diskLatencyMetricDefinitions() []MetricDefinition {
+	return []MetricDefinition{
+		{CloudWatchName: "CheckpointLag", Name: "rds_checkpoint_lag_seconds", Help: "Amount of time since the most recent checkpoint"},
+		{CloudWatchName: "DiskQueueDepth", Name: "rds_disk_queue_depth_average", Help: "Number of outstanding I/Os (read/write requests) waiting to access the disk"},
+		{CloudWatchName: "OldestReplicationSlotLag", Engines: postgresqlEngines, Name: "rds_oldest_replication_slot_lag_bytes", Help: "Lagging size of the replica lagging the most in terms of write-ahead log (WAL) data received. Applies to PostgreSQL"},
+		{CloudWatchName: "ReadLatency", Name: "rds_read_latency_seconds", Help: "Average amount of time taken per disk read I/O operation"},
+		{CloudWatchName: "WriteLatency", Name: "rds_write_latency_seconds", Help: "Average amount of time taken per disk write I/O operation"},
+	}
+}
+
+// DefaultClusterMetricDefinitions returns CloudWatch metrics collected for Aurora clusters when no metrics are configured
+func DefaultClusterMetricDefinitions() []MetricDefinition {
+	return []MetricDefinition{
+		{CloudWatchName: "AuroraVolumeBytesLeftTotal", Period: 5 * time.Minute, Name: "rds_cluster_volume_left_bytes", Help: "Remaining available space for the cluster volume"},
+		{CloudWatchName: "BacktrackChangeRecordsStored", Period: 5 * time.Minute, Name: "rds_cluster_backtrack_change_records_stored_average", Help: "Number of backtrack change records used by the cluster (Aurora MySQL only)"},
+		{CloudWatchName: "ServerlessDatabaseCapacity", Name: "rds_cluster_serverless_acu_average", Help: "Current ACU of the Aurora Serverless cluster"},
+		{CloudWatchName: "VolumeBytesUsed", Period: 5 * time.Minute, Name: "rds_cluster_volume_used_bytes", Help: "Amount of storage used by the cluster volume"},
+		{CloudWatchName: "VolumeReadIOPs", Period: 5 * time.Minute, Name: "rds_cluster_volume_read_iops_average", Help: "Number of billed read I/O operations from the cluster volume within a 5-minute interval"},
+		{CloudWatchName: "VolumeWriteIOPs", Period: 5 * time.Minute, Name: "rds_cluster_volume_write_iops_average", Help: "Number of billed write I/O operations to the cluster volume within a 5-minute interval"},
+	}
+}
+
+// DefaultProxyMetricDefinitions returns CloudWatch metrics collected for RDS proxies
+func DefaultProxyMetricDefinitions() []MetricDefinition {
+	return []MetricDefinition{
+		{CloudWatchName: "ClientConnections", Name: "rds_proxy_client_connections_average", Help: "Number of client connections to the proxy"},
+		{CloudWatchName: "DatabaseConnections", Name: "rds_proxy_database_connections_average", Help: "Number of database connections from the proxy to its targets"},
+		{CloudWatchName: "DatabaseConnectionsCurrentlyBorrowed", Name: "rds_proxy_database_connections_borrowed_average", Help: "Number of database connections of the proxy in use by client sessions"},
+		{CloudWatchName: "QueryDatabaseResponseLatency", Scale: microsecondsToSeconds, Name: "rds_proxy_query_database_response_latency_seconds", Help: "Average time for the databases to respond to queries of the proxy"},
+	}
+}
+
+// DefaultProxyTargetMetricDefinitions returns CloudWatch metrics collected for each instance target of RDS proxies
+func DefaultProxyTargetMetricDefinitions() []MetricDefinition {
+	return []MetricDefinition{
+		{CloudWatchName: "DatabaseConnections", Name: "rds_proxy_target_database_connections_average", Help: "Number of database connections from the proxy to the target"},
+		{CloudWatchName: "DatabaseConnectionsCurrentlyBorrowed", Name: "rds_proxy_target_database_connections_borrowed_average", Help: "Number of database connections of the proxy to the target in use by client sessions"},
+		{CloudWatchName: "QueryDatabaseResponseLatency", Scale: microsecondsToSeconds, Name: "rds_proxy_target_query_database_response_latency_seconds", Help: "Average time for the target to respond to queries of the proxy"},
+	}
+}
@@ -3,19 +3,59 @@ package mocks
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_cloudwatch "github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	aws_cloudwatch_types "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 )
 
+var ErrGetMetricData = errors.New("GetMetricData failed")
+
 type CloudwatchClient struct {
-	Metrics []aws_cloudwatch_types.MetricDataResult
+	Metrics        []aws_cloudwatch_types.MetricDataResult
+	PageSize       int                                     // Maximum number of results per response, next results are returned with NextToken
+	FailingQueryID string                                  // GetMetricData fails for requests containing this query ID
+	Queries        *[]aws_cloudwatch_types.MetricDataQuery // Records requested queries when set
 }
 
-// GetMetricData returns custom metrics
+// GetMetricData returns custom metrics of requested queries
+// Metrics without ID are returned for all requests
 func (m CloudwatchClient) GetMetricData(ctx context.Context, input *aws_cloudwatch.GetMetricDataInput, fn ...func(*aws_cloudwatch.Options)) (*aws_cloudwatch.GetMetricDataOutput, error) {
+	queryIDs := make(map[string]bool, len(input.MetricDataQueries))
+	for _, query := range input.MetricDataQueries {
+		queryIDs[aws.ToString(query.Id)] = true
+	}
+
+	if m.Queries != nil && input.NextToken == nil {
+		*m.Queries = append(*m.Queries, input.MetricDataQueries...)
+	}
+
+	if queryIDs[m.FailingQueryID] {
+		return nil, ErrGetMetricData
+	}
+
+	results := []aws_cloudwatch_types.MetricDataResult{}
+
+	for _, metric := range m.Metrics {
+		if metric.Id == nil || queryIDs[*metric.Id] {
+			results = append(results, metric)
+		}
+	}
+
+	offset := 0
+	if input.NextToken != nil {
+		offset, _ = strconv.Atoi(*input.NextToken)
+	}
+
 	response := &aws_cloudwatch.GetMetricDataOutput{}
-	response.MetricDataResults = m.Metrics
+	response.MetricDataResults = results[offset:]
+
+	if m.PageSize > 0 && len(response.MetricDataResults) > m.PageSize {
+		response.MetricDataResults = response.MetricDataResults[:m.PageSize]
+		response.NextToken = aws.String(strconv.Itoa(offset + m.PageSize))
+	}
 
 	return response, nil
 }
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -20,178 +19,133 @@ const (
 	Minute                         int32 = 60
 )
 
-var errUnknownMetric = errors.New("unknown metric")
+// CloudWatch dimensions of RDS metrics
+const (
+	instanceDimension    = "DBInstanceIdentifier"
+	clusterDimension     = "DBClusterIdentifier"
+	proxyDimension       = "ProxyName"
+	targetGroupDimension = "TargetGroup"
+	targetDimension      = "Target"
+)
 
 type CloudWatchMetrics struct {
-	Instances map[string]*RdsMetrics
+	Instances    map[string]RdsMetrics
+	Clusters     map[string]RdsMetrics
+	Proxies      map[string]RdsMetrics
+	ProxyTargets map[string]RdsMetrics // Identified by ProxyTargetIdentifier
 }
 
-type RdsMetrics struct {
-	CPUUtilization                   *float64
-	DatabaseConnections              *float64
-	DBLoad                           *float64
-	DBLoadCPU                        *float64
-	DBLoadNonCPU                     *float64
-	FreeableMemory                   *float64
-	FreeStorageSpace                 *float64
-	MaximumUsedTransactionIDs        *float64
-	NetworkReceiveThroughput         *float64
-	NetworkTransmitThroughput        *float64
-	ReadIOPS                         *float64
-	ReadThroughput                   *float64
-	ReplicaLag                       *float64
-	ReplicationSlotDiskUsage         *float64
-	ServerlessDatabaseCapacity       *float64
-	StorageNetworkReceiveThroughput  *float64
-	StorageNetworkTransmitThroughput *float64
-	SwapUsage                        *float64
-	TransactionLogsDiskUsage         *float64
-	WriteIOPS                        *float64
-	WriteThroughput                  *float64
+// MetricKey identifies a CloudWatch value by Prometheus metric name and CloudWatch statistic
+type MetricKey struct {
+	Name      string
+	Statistic string
 }
 
-func (m *RdsMetrics) Update(field string, value float64) error {
-	switch field {
-	case "DBLoad":
-		m.DBLoad = &value
-	case "DBLoadCPU":
-		m.DBLoadCPU = &value
-	case "DBLoadNonCPU":
-		m.DBLoadNonCPU = &value
-	case "CPUUtilization":
-		m.CPUUtilization = &value
-	case "DatabaseConnections":
-		m.DatabaseConnections = &value
-	case "FreeStorageSpace":
-		m.FreeStorageSpace = &value
-	case "FreeableMemory":
-		m.FreeableMemory = &value
-	case "NetworkReceiveThroughput":
-		m.NetworkReceiveThroughput = &value
-	case "NetworkTransmitThroughput":
-		m.NetworkTransmitThroughput = &value
-	case "SwapUsage":
-		m.SwapUsage = &value
-	case "WriteIOPS":
-		m.WriteIOPS = &value
-	case "ReadIOPS":
-		m.ReadIOPS = &value
-	case "ReplicaLag":
-		m.ReplicaLag = &value
-	case "ReplicationSlotDiskUsage":
-		m.ReplicationSlotDiskUsage = &value
-	case "MaximumUsedTransactionIDs":
-		m.MaximumUsedTransactionIDs = &value
-	case "ReadThroughput":
-		m.ReadThroughput = &value
-	case "WriteThroughput":
-		m.WriteThroughput = &value
-	case "TransactionLogsDiskUsage":
-		m.TransactionLogsDiskUsage = &value
-	case "ServerlessDatabaseCapacity":
-		m.ServerlessDatabaseCapacity = &value
-	case "StorageNetworkReceiveThroughput":
-		m.StorageNetworkReceiveThroughput = &value
-	case "StorageNetworkTransmitThroughput":
-		m.StorageNetworkTransmitThroughput = &value
-	default:
-		return fmt.Errorf("can't process '%s' metrics: %w", field, errUnknownMetric)
-	}
-
-	return nil
+// Datapoint is the latest CloudWatch value of a metric
+type Datapoint struct {
+	Value     float64
+	Timestamp time.Time // Start of the CloudWatch period of the value, zero when CloudWatch didn't return it
 }
 
-// getCloudWatchMetricsName returns names of Cloudwatch metrics to collect
-func getCloudWatchMetricsName() [21]string {
-	return [21]string{
-		"CPUUtilization",
-		"DBLoad",
-		"DBLoadCPU",
-		"DBLoadNonCPU",
-		"DatabaseConnections",
-		"FreeStorageSpace",
-		"FreeableMemory",
-		"MaximumUsedTransactionIDs",
-		"NetworkReceiveThroughput",
-		"NetworkTransmitThroughput",
-		"ReadIOPS",
-		"ReadThroughput",
-		"ReplicaLag",
-		"ReplicationSlotDiskUsage",
-		"SwapUsage",
-		"ServerlessDatabaseCapacity",
-		"StorageNetworkReceiveThroughput",
-		"StorageNetworkTransmitThroughput",
-		"TransactionLogsDiskUsage",
-		"WriteIOPS",
-		"WriteThroughput",
+// RdsMetrics contains latest CloudWatch datapoints of an instance or a cluster
+type RdsMetrics map[MetricKey]Datapoint
+
+// generateCloudWatchQuery return the cloudwatch query for a specific instance's, cluster's or proxy's metric
+func generateCloudWatchQuery(queryID *string, definition MetricDefinition, statistic string, dimension string, resource Resource) CloudWatchMetricRequest {
+	dimensions := resource.Dimensions
+	if len(dimensions) == 0 {
+		dimensions = []Dimension{{Name: dimension, Value: resource.Identifier}}
+	}
+
+	queryDimensions := make([]aws_cloudwath_types.Dimension, 0, len(dimensions))
+	for _, d := range dimensions {
+		queryDimensions = append(queryDimensions, aws_cloudwath_types.Dimension{Name: aws.String(d.Name), Value: aws.String(d.Value)})
 	}
-}
 
-// generateCloudWatchQueryForInstance return the cloudwatch query for a specific instance's metric
-func generateCloudWatchQueryForInstance(queryID *string, metricName string, dbIdentifier string) CloudWatchMetricRequest {
 	query := &aws_cloudwath_types.MetricDataQuery{
 		Id: queryID,
 		MetricStat: &aws_cloudwath_types.MetricStat{
 			Metric: &aws_cloudwath_types.Metric{
 				Namespace:  aws.String("AWS/RDS"),
-				MetricName: aws.String(metricName),
-				Dimensions: []aws_cloudwath_types.Dimension{
-					{
-						Name:  aws.String("DBInstanceIdentifier"),
-						Value: aws.String(dbIdentifier),
-					},
-				},
+				MetricName: aws.String(definition.CloudWatchName),
+				Dimensions: queryDimensions,
 			},
-			Stat:   aws.String("Average"), // Specify the statistic to retrieve
-			Period: aws.Int32(Minute),     // Specify the period of the metric. Granularity - 1 minute
+			Stat:   aws.String(statistic),
+			Period: aws.Int32(definition.GetPeriod()),
 		},
 	}
 
 	return CloudWatchMetricRequest{
-		Dbidentifier: dbIdentifier,
-		MetricName:   metricName,
+		Dbidentifier: resource.Identifier,
+		MetricName:   definition.CloudWatchName,
+		Definition:   definition,
+		Statistic:    statistic,
 		Query:        *query,
 	}
 }
 
-// generateCloudWatchQueriesForInstances returns all cloudwatch queries for specified instances
-func generateCloudWatchQueriesForInstances(dbIdentifiers []string) map[string]CloudWatchMetricRequest {
+// generateCloudWatchQueries returns all cloudwatch queries for specified instances or clusters
+func generateCloudWatchQueries(definitions []MetricDefinition, dimension string, resources []Resource) map[string]CloudWatchMetricRequest {
 	queries := make(map[string]CloudWatchMetricRequest)
 
-	metrics := getCloudWatchMetricsName()
+	for i, resource := range resources {
+		// Query IDs must be unique, so they use the position of the definition statistic since several definitions may query the same CloudWatch metric
+		j := 0
+
+		for _, definition := range definitions {
+			for _, statistic := range definition.GetStatistics() {
+				queryID := aws.String(fmt.Sprintf("m%d_%d", j, i))
+				j++
 
-	for i, dbIdentifier := range dbIdentifiers {
-		for _, metricName := range metrics {
-			queryID := aws.String(fmt.Sprintf("%s_%d", strings.ToLower(metricName), i))
+				if !definition.Matches(resource) {
+					continue
+				}
 
-			query := generateCloudWatchQueryForInstance(queryID, metricName, dbIdentifier)
+				query := generateCloudWatchQuery(queryID, definition, statistic, dimension, resource)
 
-			queries[*queryID] = query
+				queries[*queryID] = query
+			}
 		}
 	}
 
 	return queries
 }
 
-func NewRDSFetcher(client CloudWatchClient, logger slog.Logger) *RdsFetcher {
+// getQueryWindow returns the time range to query to get at least one datapoint of each metric
+func getQueryWindow(definitions []MetricDefinition) time.Duration {
+	period := Minute
+
+	for _, definition := range definitions {
+		period = max(period, definition.GetPeriod())
+	}
+
+	return 3 * time.Duration(period) * time.Second
+}
+
+func NewRDSFetcher(ctx context.Context, client CloudWatchClient, logger slog.Logger, definitions []MetricDefinition) *RdsFetcher {
 	return &RdsFetcher{
-		client: client,
-		logger: &logger,
+		ctx:         ctx,
+		client:      client,
+		logger:      &logger,
+		definitions: definitions,
 	}
 }
 
 type RdsFetcher struct {
-	client     CloudWatchClient
-	statistics Statistics
-	logger     *slog.Logger
+	ctx         context.Context
+	client      CloudWatchClient
+	statistics  Statistics
+	logger      *slog.Logger
+	definitions []MetricDefinition
 }
 
 func (c *RdsFetcher) GetStatistics() *Statistics {
 	return &c.statistics
 }
 
-func (c *RdsFetcher) updateMetricsWithCloudWatchQueriesResult(metrics map[string]*RdsMetrics, requests map[string]CloudWatchMetricRequest, startTime *time.Time, endTime *time.Time, chunk []string) error {
+// updateMetricsWithCloudWatchQueriesResult fetches all pages of results of the chunk queries
+// Queries without complete data are counted as incomplete results
+func (c *RdsFetcher) updateMetricsWithCloudWatchQueriesResult(metrics map[string]RdsMetrics, requests map[string]CloudWatchMetricRequest, startTime *time.Time, endTime *time.Time, chunk []string) error {
 	params := &aws_cloudwatch.GetMetricDataInput{
 		StartTime:         startTime,
 		EndTime:           endTime,
@@ -204,45 +158,165 @@ func (c *RdsFetcher) updateMetricsWithCloudWatchQueriesResult(metrics map[string
 		params.MetricDataQueries = append(params.MetricDataQueries, query)
 	}
 
-	resp, err := c.client.GetMetricData(context.TODO(), params)
-	if err != nil {
-		return fmt.Errorf("error calling GetMetricData: %w", err)
-	}
+	// Status code of the last result of each query
+	statusCodes := make(map[string]aws_cloudwath_types.StatusCode, len(chunk))
 
-	for _, m := range resp.MetricDataResults {
-		if m.Values == nil {
-			c.logger.Warn("cloudwatch value is empty", "metric", *m.Label)
+	var err error
 
-			continue
-		}
+	for {
+		var resp *aws_cloudwatch.GetMetricDataOutput
 
-		val := requests[*m.Id]
+		resp, err = c.client.GetMetricData(c.ctx, params)
+		if err != nil {
+			err = fmt.Errorf("error calling GetMetricData: %w", err)
 
-		_, instanceMetricExists := metrics[val.Dbidentifier]
-		if !instanceMetricExists {
-			metrics[val.Dbidentifier] = &RdsMetrics{}
+			break
 		}
 
-		if len(m.Values) > 0 {
-			err := metrics[val.Dbidentifier].Update(val.MetricName, m.Values[0])
-			if err != nil {
-				return fmt.Errorf("failed to process metrics %s: %w", val.MetricName, err)
+		for _, m := range resp.MetricDataResults {
+			val, found := requests[aws.ToString(m.Id)]
+			if !found {
+				c.logger.Warn("unexpected cloudwatch result", "id", aws.ToString(m.Id))
+
+				continue
+			}
+
+			statusCodes[*m.Id] = m.StatusCode
+
+			if isIncomplete(m.StatusCode) {
+				c.logger.Warn("incomplete cloudwatch result", "metric", val.MetricName, "identifier", val.Dbidentifier, "status", m.StatusCode)
+			}
+
+			if m.Values == nil {
+				c.logger.Warn("cloudwatch value is empty", "metric", aws.ToString(m.Label))
+
+				continue
+			}
+
+			if len(m.Values) > 0 {
+				updateDatapoint(metrics, val, m)
 			}
 		}
+
+		if resp.NextToken == nil {
+			break
+		}
+
+		params.NextToken = resp.NextToken
+	}
+
+	for _, key := range chunk {
+		statusCode, returned := statusCodes[key]
+		if (!returned && err != nil) || isIncomplete(statusCode) {
+			c.statistics.IncompleteResults++
+		}
+	}
+
+	return err
+}
+
+// isIncomplete returns true if CloudWatch didn't return all data of the query
+func isIncomplete(statusCode aws_cloudwath_types.StatusCode) bool {
+	switch statusCode {
+	case aws_cloudwath_types.StatusCodePartialData, aws_cloudwath_types.StatusCodeInternalError, aws_cloudwath_types.StatusCodeForbidden:
+		return true
+	default:
+		return false
+	}
+}
+
+// updateDatapoint stores the latest datapoint of the result
+// Results are sorted by descending timestamps, so the first value of a result is its latest one,
+// but results of a query can be split across pages
+func updateDatapoint(metrics map[string]RdsMetrics, request CloudWatchMetricRequest, result aws_cloudwath_types.MetricDataResult) {
+	datapoint := Datapoint{Value: request.Definition.Convert(result.Values[0])}
+	if len(result.Timestamps) > 0 {
+		datapoint.Timestamp = result.Timestamps[0]
+	}
+
+	_, instanceMetricExists := metrics[request.Dbidentifier]
+	if !instanceMetricExists {
+		metrics[request.Dbidentifier] = make(RdsMetrics)
+	}
+
+	key := MetricKey{Name: request.Definition.Name, Statistic: request.Statistic}
+
+	if current, found := metrics[request.Dbidentifier][key]; found && !datapoint.Timestamp.After(current.Timestamp) {
+		return
+	}
+
+	metrics[request.Dbidentifier][key] = datapoint
+}
+
+// GetRDSInstanceMetrics returns CloudWatch metrics of instances
+// Metrics fetched before an error are returned with the error
+func (c *RdsFetcher) GetRDSInstanceMetrics(instances []Resource) (CloudWatchMetrics, error) {
+	metrics, err := c.getMetrics(instanceDimension, instances)
+
+	return CloudWatchMetrics{
+		Instances: metrics,
+	}, err
+}
+
+// GetRDSClusterMetrics returns CloudWatch metrics of clusters
+// Metrics fetched before an error are returned with the error
+func (c *RdsFetcher) GetRDSClusterMetrics(clusters []Resource) (CloudWatchMetrics, error) {
+	metrics, err := c.getMetrics(clusterDimension, clusters)
+
+	return CloudWatchMetrics{
+		Clusters: metrics,
+	}, err
+}
+
+// GetRDSProxyMetrics returns CloudWatch metrics of proxies
+// Metrics fetched before an error are returned with the error
+func (c *RdsFetcher) GetRDSProxyMetrics(proxies []Resource) (CloudWatchMetrics, error) {
+	metrics, err := c.getMetrics(proxyDimension, proxies)
+
+	return CloudWatchMetrics{
+		Proxies: metrics,
+	}, err
+}
+
+// GetRDSProxyTargetMetrics returns CloudWatch metrics of proxy targets created with NewProxyTargetResource
+// Metrics fetched before an error are returned with the error
+func (c *RdsFetcher) GetRDSProxyTargetMetrics(targets []Resource) (CloudWatchMetrics, error) {
+	metrics, err := c.getMetrics(proxyDimension, targets)
+
+	return CloudWatchMetrics{
+		ProxyTargets: metrics,
+	}, err
+}
+
+// NewProxyTargetResource returns the resource of an instance target of a proxy target group
+func NewProxyTargetResource(proxyName string, targetGroup string, dbidentifier string) Resource {
+	return Resource{
+		Identifier: ProxyTargetIdentifier(proxyName, targetGroup, dbidentifier),
+		Dimensions: []Dimension{
+			{Name: proxyDimension, Value: proxyName},
+			{Name: targetGroupDimension, Value: targetGroup},
+			{Name: targetDimension, Value: "db:" + dbidentifier}, // CloudWatch prefixes instance targets with "db:"
+		},
 	}
+}
 
-	return nil
+// ProxyTargetIdentifier returns the identifier of proxy target metrics
+func ProxyTargetIdentifier(proxyName string, targetGroup string, dbidentifier string) string {
+	return proxyName + "/" + targetGroup + "/" + dbidentifier
 }
 
-func (c *RdsFetcher) GetRDSInstanceMetrics(dbIdentifiers []string) (CloudWatchMetrics, error) {
-	metrics := make(map[string]*RdsMetrics)
+// getMetrics returns CloudWatch metrics of instances, clusters or proxies by identifier
+// A failing chunk of queries doesn't prevent fetching other chunks, their errors are returned with fetched metrics
+func (c *RdsFetcher) getMetrics(dimension string, resources []Resource) (map[string]RdsMetrics, error) {
+	metrics := make(map[string]RdsMetrics)
 
-	cloudWatchQueries := generateCloudWatchQueriesForInstances(dbIdentifiers)
-	startTime := aws.Time(time.Now().Add(-3 * time.Minute)) // Start time - 1 hour ago
-	endTime := aws.Time(time.Now())                         // End time - now
+	cloudWatchQueries := generateCloudWatchQueries(c.definitions, dimension, resources)
+	startTime := aws.Time(time.Now().Add(-getQueryWindow(c.definitions))) // Start time - 3 periods ago
+	endTime := aws.Time(time.Now())                                       // End time - now
 	chunkSize := MaxQueriesPerCloudwatchRequest
 
-	cloudWatchAPICalls := float64(0)
+	var errs []error
+
 	chunk := make([]string, 0, chunkSize)
 
 	for query := range cloudWatchQueries {
@@ -251,11 +325,10 @@ func (c *RdsFetcher) GetRDSInstanceMetrics(dbIdentifiers []string) (CloudWatchMe
 		if len(chunk) == chunkSize {
 			err := c.updateMetricsWithCloudWatchQueriesResult(metrics, cloudWatchQueries, startTime, endTime, chunk)
 			if err != nil {
-				return CloudWatchMetrics{}, fmt.Errorf("can't fetch Cloudwatch metrics: %w", err)
+				errs = append(errs, err)
 			}
 
 			chunk = nil
-			cloudWatchAPICalls += 1
 		}
 	}
 
@@ -263,13 +336,13 @@ func (c *RdsFetcher) GetRDSInstanceMetrics(dbIdentifiers []string) (CloudWatchMe
 	if len(chunk) > 0 {
 		err := c.updateMetricsWithCloudWatchQueriesResult(metrics, cloudWatchQueries, startTime, endTime, chunk)
 		if err != nil {
-			return CloudWatchMetrics{}, fmt.Errorf("can't fetch Cloudwatch metrics: %w", err)
+			errs = append(errs, err)
 		}
+	}
 
-		c.statistics.CloudWatchAPICall++
+	if len(errs) > 0 {
+		return metrics, fmt.Errorf("can't fetch Cloudwatch metrics: %w", errors.Join(errs...))
 	}
 
-	return CloudWatchMetrics{
-		Instances: metrics,
-	}, nil
+	return metrics, nil
 }
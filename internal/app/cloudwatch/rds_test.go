@@ -1,9 +1,11 @@
 package cloudwatch_test
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_cloudwatch_types "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
@@ -14,176 +16,449 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-var db1ExpecteRdsMetrics = cloudwatch.RdsMetrics{
-	CPUUtilization:            aws.Float64(10),
-	DBLoad:                    aws.Float64(1),
-	DBLoadCPU:                 aws.Float64(2),
-	DBLoadNonCPU:              aws.Float64(4),
-	DatabaseConnections:       aws.Float64(42),
-	FreeStorageSpace:          aws.Float64(5),
-	FreeableMemory:            aws.Float64(10),
-	MaximumUsedTransactionIDs: aws.Float64(1000000),
-	ReadIOPS:                  aws.Float64(100),
-	ReadThroughput:            aws.Float64(101),
-	ReplicaLag:                aws.Float64(42),
-	ReplicationSlotDiskUsage:  aws.Float64(100),
-	SwapUsage:                 aws.Float64(10),
-	TransactionLogsDiskUsage:  aws.Float64(24),
-	WriteIOPS:                 aws.Float64(11),
-	WriteThroughput:           aws.Float64(12),
-}
-
-var db2ExpecteRdsMetrics = cloudwatch.RdsMetrics{
-	CPUUtilization:            aws.Float64(40),
-	DBLoad:                    aws.Float64(2),
-	DBLoadCPU:                 aws.Float64(8),
-	DBLoadNonCPU:              aws.Float64(1),
-	DatabaseConnections:       aws.Float64(1000),
-	FreeStorageSpace:          aws.Float64(10),
-	FreeableMemory:            aws.Float64(10),
-	MaximumUsedTransactionIDs: aws.Float64(1000000),
-	ReadIOPS:                  aws.Float64(100),
-	ReadThroughput:            aws.Float64(101),
-	ReplicaLag:                aws.Float64(42),
-	ReplicationSlotDiskUsage:  aws.Float64(100),
-	SwapUsage:                 aws.Float64(10),
-	TransactionLogsDiskUsage:  aws.Float64(24),
-	WriteIOPS:                 aws.Float64(11),
-	WriteThroughput:           aws.Float64(12),
+// CloudWatch values by CloudWatch metric name
+var db1CloudWatchValues = map[string]float64{
+	"CPUUtilization":            10,
+	"CheckpointLag":             30,
+	"DBLoad":                    1,
+	"DBLoadCPU":                 2,
+	"DBLoadNonCPU":              4,
+	"DatabaseConnections":       42,
+	"DiskQueueDepth":            3,
+	"FreeStorageSpace":          5,
+	"FreeableMemory":            10,
+	"MaximumUsedTransactionIDs": 1000000,
+	"OldestReplicationSlotLag":  2048,
+	"ReadIOPS":                  100,
+	"ReadLatency":               0.002,
+	"ReadThroughput":            101,
+	"ReplicaLag":                42,
+	"ReplicationSlotDiskUsage":  100,
+	"SwapUsage":                 10,
+	"TransactionLogsDiskUsage":  24,
+	"WriteIOPS":                 11,
+	"WriteLatency":              0.004,
+	"WriteThroughput":           12,
+}
+
+var db2CloudWatchValues = map[string]float64{
+	"CPUUtilization":            40,
+	"DBLoad":                    2,
+	"DBLoadCPU":                 8,
+	"DBLoadNonCPU":              1,
+	"DatabaseConnections":       1000,
+	"FreeStorageSpace":          10,
+	"FreeableMemory":            10,
+	"MaximumUsedTransactionIDs": 1000000,
+	"ReadIOPS":                  100,
+	"ReadThroughput":            101,
+	"ReplicaLag":                42,
+	"ReplicationSlotDiskUsage":  100,
+	"SwapUsage":                 10,
+	"TransactionLogsDiskUsage":  24,
+	"WriteIOPS":                 11,
+	"WriteThroughput":           12,
 }
 
 // generateMockedMetricsForInstance returns cloudwatch API output for the instance
-func generateMockedMetricsForInstance(id int, m cloudwatch.RdsMetrics) []aws_cloudwatch_types.MetricDataResult {
-	metrics := []aws_cloudwatch_types.MetricDataResult{
-		{
-			Id:     aws.String(fmt.Sprintf("cpuutilization_%d", id)),
-			Label:  aws.String("CPUUtilization"),
-			Values: []float64{*m.CPUUtilization},
-		},
-		{
-			Id:     aws.String(fmt.Sprintf("dbload_%d", id)),
-			Label:  aws.String("DBLoad"),
-			Values: []float64{*m.DBLoad},
-		},
-		{
-			Id:     aws.String(fmt.Sprintf("dbloadcpu_%d", id)),
-			Label:  aws.String("DBLoadCPU"),
-			Values: []float64{*m.DBLoadCPU},
+func generateMockedMetricsForInstance(id int, definitions []cloudwatch.MetricDefinition, values map[string]float64) []aws_cloudwatch_types.MetricDataResult {
+	metrics := []aws_cloudwatch_types.MetricDataResult{}
+
+	for i, definition := range definitions {
+		value, found := values[definition.CloudWatchName]
+		if !found {
+			continue
+		}
+
+		metrics = append(metrics, aws_cloudwatch_types.MetricDataResult{
+			Id:     aws.String(fmt.Sprintf("m%d_%d", i, id)),
+			Label:  aws.String(definition.CloudWatchName),
+			Values: []float64{value},
+		})
+	}
+
+	return metrics
+}
+
+func TestGetDBInstanceTypeInformation(t *testing.T) {
+	resources := []cloudwatch.Resource{}
+	data := []aws_cloudwatch_types.MetricDataResult{}
+	definitions := cloudwatch.DefaultMetricDefinitions()
+
+	// Generate instances metrics
+	instances := make(map[string]map[string]float64)
+	instances["db1"] = db1CloudWatchValues
+	instances["db2"] = db2CloudWatchValues
+
+	// Generate Cloudwatch API output metrics
+	i := 0
+
+	for id := range instances {
+		resources = append(resources, cloudwatch.Resource{Identifier: id, Engine: "postgres"})
+		instancesMetrics := generateMockedMetricsForInstance(i, definitions, instances[id])
+
+		data = append(data, instancesMetrics...)
+		i++
+	}
+
+	client := cloudwatch_mock.CloudwatchClient{Metrics: data}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, slog.Logger{}, definitions)
+	result, err := fetcher.GetRDSInstanceMetrics(resources)
+
+	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
+
+	for id, values := range instances {
+		for _, definition := range definitions {
+			key := cloudwatch.MetricKey{Name: definition.Name, Statistic: "Average"}
+
+			value, found := values[definition.CloudWatchName]
+			if !found {
+				assert.NotContains(t, result.Instances[id], key, "%s should not be set", definition.Name)
+
+				continue
+			}
+
+			assert.Equal(t, value, result.Instances[id][key].Value, "%s mismatch", definition.Name)
+		}
+	}
+}
+
+func TestGetRDSInstanceMetricsWithCustomDefinitions(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "CPUUtilization", Statistic: "Maximum", Name: "rds_cpu_usage_ratio_max", Scale: 0.01},
+		{CloudWatchName: "DiskQueueDepth", Period: 5 * time.Minute, Name: "rds_disk_queue_depth_average"},
+	}
+
+	client := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{50}},
+		{Id: aws.String("m1_0"), Label: aws.String("DiskQueueDepth"), Values: []float64{3}},
+	}}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, slog.Logger{}, definitions)
+	result, err := fetcher.GetRDSInstanceMetrics([]cloudwatch.Resource{{Identifier: "db1", Engine: "postgres"}})
+
+	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
+	assert.InDelta(t, 0.5, result.Instances["db1"][cloudwatch.MetricKey{Name: "rds_cpu_usage_ratio_max", Statistic: "Maximum"}].Value, 0.0001, "should convert units with scale")
+	assert.Equal(t, float64(3), result.Instances["db1"][cloudwatch.MetricKey{Name: "rds_disk_queue_depth_average", Statistic: "Average"}].Value, "should collect custom CloudWatch metrics")
+}
+
+func TestGetRDSInstanceMetricsWithSeveralStatistics(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "DatabaseConnections", Statistics: []string{"Average", "Maximum", "p99"}, Name: "rds_database_connections"},
+		{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average"},
+	}
+
+	client := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("DatabaseConnections"), Values: []float64{10}},
+		{Id: aws.String("m1_0"), Label: aws.String("DatabaseConnections"), Values: []float64{42}},
+		{Id: aws.String("m2_0"), Label: aws.String("DatabaseConnections"), Values: []float64{40}},
+		{Id: aws.String("m3_0"), Label: aws.String("CPUUtilization"), Values: []float64{15}},
+	}}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, slog.Logger{}, definitions)
+	result, err := fetcher.GetRDSInstanceMetrics([]cloudwatch.Resource{{Identifier: "db1", Engine: "postgres"}})
+
+	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
+
+	expected := cloudwatch.RdsMetrics{
+		{Name: "rds_database_connections", Statistic: "Average"}:      {Value: 10},
+		{Name: "rds_database_connections", Statistic: "Maximum"}:      {Value: 42},
+		{Name: "rds_database_connections", Statistic: "p99"}:          {Value: 40},
+		{Name: "rds_cpu_usage_percent_average", Statistic: "Average"}: {Value: 15},
+	}
+	assert.Equal(t, expected, result.Instances["db1"], "should collect each statistic of CloudWatch metrics")
+}
+
+func TestGetRDSInstanceMetricsWithDatapointTimestamps(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average"},
+	}
+
+	latest := time.Date(2024, 1, 1, 12, 3, 0, 0, time.UTC)
+	client := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{15, 10}, Timestamps: []time.Time{latest, latest.Add(-time.Minute)}},
+	}}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, slog.Logger{}, definitions)
+	result, err := fetcher.GetRDSInstanceMetrics([]cloudwatch.Resource{{Identifier: "db1", Engine: "postgres"}})
+
+	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
+
+	expected := cloudwatch.Datapoint{Value: 15, Timestamp: latest}
+	assert.Equal(t, expected, result.Instances["db1"][cloudwatch.MetricKey{Name: "rds_cpu_usage_percent_average", Statistic: "Average"}], "should keep the latest datapoint with its timestamp")
+}
+
+func TestGetRDSInstanceMetricsWithPagination(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average"},
+		{CloudWatchName: "DatabaseConnections", Name: "rds_database_connections_average"},
+	}
+
+	latest := time.Date(2024, 1, 1, 12, 3, 0, 0, time.UTC)
+	client := cloudwatch_mock.CloudwatchClient{PageSize: 1, Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{15}, Timestamps: []time.Time{latest}, StatusCode: aws_cloudwatch_types.StatusCodePartialData},
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{10}, Timestamps: []time.Time{latest.Add(-time.Minute)}, StatusCode: aws_cloudwatch_types.StatusCodeComplete},
+		{Id: aws.String("m1_0"), Label: aws.String("DatabaseConnections"), Values: []float64{42}, Timestamps: []time.Time{latest}, StatusCode: aws_cloudwatch_types.StatusCodeComplete},
+	}}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, *slog.Default(), definitions)
+	result, err := fetcher.GetRDSInstanceMetrics([]cloudwatch.Resource{{Identifier: "db1", Engine: "postgres"}})
+
+	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
+	assert.Zero(t, fetcher.GetStatistics().IncompleteResults, "should not count results completed on next pages")
+
+	expected := cloudwatch.RdsMetrics{
+		{Name: "rds_cpu_usage_percent_average", Statistic: "Average"}:    {Value: 15, Timestamp: latest},
+		{Name: "rds_database_connections_average", Statistic: "Average"}: {Value: 42, Timestamp: latest},
+	}
+	assert.Equal(t, expected, result.Instances["db1"], "should keep the latest datapoint of paginated results")
+}
+
+func TestGetRDSInstanceMetricsWithIncompleteResults(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average"},
+		{CloudWatchName: "DatabaseConnections", Name: "rds_database_connections_average"},
+	}
+
+	client := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{15}, StatusCode: aws_cloudwatch_types.StatusCodeComplete},
+		{Id: aws.String("m1_0"), Label: aws.String("DatabaseConnections"), Values: []float64{}, StatusCode: aws_cloudwatch_types.StatusCodeInternalError},
+	}}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, *slog.Default(), definitions)
+	result, err := fetcher.GetRDSInstanceMetrics([]cloudwatch.Resource{{Identifier: "db1", Engine: "postgres"}})
+
+	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
+	assert.Equal(t, float64(1), fetcher.GetStatistics().IncompleteResults, "should count incomplete results")
+	assert.Equal(t, cloudwatch.RdsMetrics{{Name: "rds_cpu_usage_percent_average", Statistic: "Average"}: {Value: 15}}, result.Instances["db1"], "should keep complete results")
+}
+
+func TestGetRDSInstanceMetricsWithFailingChunk(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average"},
+	}
+
+	// One query per instance, so instances need two chunks of queries
+	instancesCount := cloudwatch.MaxQueriesPerCloudwatchRequest + 1
+	resources := make([]cloudwatch.Resource, instancesCount)
+	results := make([]aws_cloudwatch_types.MetricDataResult, instancesCount)
+
+	for i := range instancesCount {
+		resources[i] = cloudwatch.Resource{Identifier: fmt.Sprintf("db%d", i), Engine: "postgres"}
+		results[i] = aws_cloudwatch_types.MetricDataResult{Id: aws.String(fmt.Sprintf("m0_%d", i)), Label: aws.String("CPUUtilization"), Values: []float64{10}}
+	}
+
+	client := cloudwatch_mock.CloudwatchClient{Metrics: results, FailingQueryID: "m0_0"}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, slog.Logger{}, definitions)
+	result, err := fetcher.GetRDSInstanceMetrics(resources)
+
+	require.ErrorIs(t, err, cloudwatch_mock.ErrGetMetricData, "should return the error of the failing chunk")
+	assert.NotEmpty(t, result.Instances, "should return metrics of other chunks")
+	assert.NotContains(t, result.Instances, "db0", "should not return metrics of the failing chunk")
+	assert.Equal(t, float64(instancesCount), float64(len(result.Instances))+fetcher.GetStatistics().IncompleteResults, "should count queries of the failing chunk as incomplete results")
+}
+
+func TestGetRDSInstanceMetricsWithEngineFilter(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average"},
+		{CloudWatchName: "AuroraReplicaLag", Engines: []string{"aurora"}, Scale: 0.001, Name: "rds_aurora_replica_lag_seconds"},
+	}
+
+	client := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{10}},
+		{Id: aws.String("m1_0"), Label: aws.String("AuroraReplicaLag"), Values: []float64{20}},
+		{Id: aws.String("m0_1"), Label: aws.String("CPUUtilization"), Values: []float64{30}},
+		{Id: aws.String("m1_1"), Label: aws.String("AuroraReplicaLag"), Values: []float64{40}},
+	}}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, *slog.Default(), definitions)
+	result, err := fetcher.GetRDSInstanceMetrics([]cloudwatch.Resource{
+		{Identifier: "aurora1", Engine: "aurora-postgresql"},
+		{Identifier: "postgres1", Engine: "postgres"},
+	})
+
+	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
+
+	cpu := cloudwatch.MetricKey{Name: "rds_cpu_usage_percent_average", Statistic: "Average"}
+	replicaLag := cloudwatch.MetricKey{Name: "rds_aurora_replica_lag_seconds", Statistic: "Average"}
+
+	assert.Equal(t, cloudwatch.RdsMetrics{cpu: {Value: 10}, replicaLag: {Value: 0.02}}, result.Instances["aurora1"], "should collect engine-specific metrics of matching instances")
+	assert.Equal(t, cloudwatch.RdsMetrics{cpu: {Value: 30}}, result.Instances["postgres1"], "should not collect engine-specific metrics of other instances")
+}
+
+func TestGetRDSClusterMetrics(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "VolumeBytesUsed", Name: "rds_cluster_volume_used_bytes"},
+	}
+
+	client := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("VolumeBytesUsed"), Values: []float64{1024}},
+		{Id: aws.String("m0_1"), Label: aws.String("VolumeBytesUsed"), Values: []float64{2048}},
+	}}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, slog.Logger{}, definitions)
+	result, err := fetcher.GetRDSClusterMetrics([]cloudwatch.Resource{{Identifier: "cluster1", Engine: "aurora-postgresql"}, {Identifier: "cluster2", Engine: "aurora-mysql"}})
+
+	require.NoError(t, err, "GetRDSClusterMetrics must succeed")
+	assert.Empty(t, result.Instances, "should not return instance metrics")
+
+	key := cloudwatch.MetricKey{Name: "rds_cluster_volume_used_bytes", Statistic: "Average"}
+	assert.Equal(t, float64(1024), result.Clusters["cluster1"][key].Value, "cluster1 volume mismatch")
+	assert.Equal(t, float64(2048), result.Clusters["cluster2"][key].Value, "cluster2 volume mismatch")
+}
+
+func TestGetRDSProxyTargetMetrics(t *testing.T) {
+	definitions := []cloudwatch.MetricDefinition{
+		{CloudWatchName: "DatabaseConnections", Name: "rds_proxy_target_database_connections_average"},
+	}
+
+	var queries []aws_cloudwatch_types.MetricDataQuery
+
+	client := cloudwatch_mock.CloudwatchClient{
+		Metrics: []aws_cloudwatch_types.MetricDataResult{
+			{Id: aws.String("m0_0"), Label: aws.String("DatabaseConnections"), Values: []float64{12}},
 		},
+		Queries: &queries,
+	}
+	fetcher := cloudwatch.NewRDSFetcher(context.TODO(), client, slog.Logger{}, definitions)
+	result, err := fetcher.GetRDSProxyTargetMetrics([]cloudwatch.Resource{cloudwatch.NewProxyTargetResource("proxy1", "default", "db1")})
+
+	require.NoError(t, err, "GetRDSProxyTargetMetrics must succeed")
+	require.Len(t, queries, 1, "One query per target")
+
+	dimensions := map[string]string{}
+	for _, dimension := range queries[0].MetricStat.Metric.Dimensions {
+		dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+	}
+
+	assert.Equal(t, map[string]string{"ProxyName": "proxy1", "TargetGroup": "default", "Target": "db:db1"}, dimensions, "Target dimensions mismatch")
+
+	key := cloudwatch.MetricKey{Name: "rds_proxy_target_database_connections_average", Statistic: "Average"}
+	assert.Equal(t, float64(12), result.ProxyTargets[cloudwatch.ProxyTargetIdentifier("proxy1", "default", "db1")][key].Value, "Connections mismatch")
+}
+
+func TestValidateMetricDefinitions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		definitions []cloudwatch.MetricDefinition
+		expectError bool
+	}{
 		{
-			Id:     aws.String(fmt.Sprintf("dbloadnoncpu_%d", id)),
-			Label:  aws.String("DBLoadNonCPU"),
-			Values: []float64{*m.DBLoadNonCPU},
+			name:        "Default definitions",
+			definitions: cloudwatch.DefaultMetricDefinitions(),
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("databaseconnections_%d", id)),
-			Label:  aws.String("DatabaseConnections"),
-			Values: []float64{*m.DatabaseConnections},
+			name:        "Default cluster definitions",
+			definitions: cloudwatch.DefaultClusterMetricDefinitions(),
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("freestoragespace_%d", id)),
-			Label:  aws.String("FreeStorageSpace"),
-			Values: []float64{*m.FreeStorageSpace},
+			name:        "Default proxy definitions",
+			definitions: cloudwatch.DefaultProxyMetricDefinitions(),
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("freeablememory_%d", id)),
-			Label:  aws.String("FreeableMemory"),
-			Values: []float64{*m.FreeableMemory},
+			name:        "Default proxy target definitions",
+			definitions: cloudwatch.DefaultProxyTargetMetricDefinitions(),
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("maximumusedtransactionids_%d", id)),
-			Label:  aws.String("MaximumUsedTransactionIDs"),
-			Values: []float64{*m.MaximumUsedTransactionIDs},
+			name:        "Percentile statistic",
+			definitions: []cloudwatch.MetricDefinition{{CloudWatchName: "ReadLatency", Statistic: "p99.9", Name: "rds_read_latency_p999_seconds"}},
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("readiops_%d", id)),
-			Label:  aws.String("ReadIOPS"),
-			Values: []float64{*m.ReadIOPS},
+			name:        "Several statistics",
+			definitions: []cloudwatch.MetricDefinition{{CloudWatchName: "DatabaseConnections", Statistics: []string{"Average", "Maximum", "p99"}, Name: "rds_database_connections"}},
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("readthroughput_%d", id)),
-			Label:  aws.String("ReadThroughput"),
-			Values: []float64{*m.ReadThroughput},
+			name:        "Statistic and statistics",
+			definitions: []cloudwatch.MetricDefinition{{CloudWatchName: "DatabaseConnections", Statistic: "Average", Statistics: []string{"Maximum"}, Name: "rds_database_connections"}},
+			expectError: true,
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("replicalag_%d", id)),
-			Label:  aws.String("ReplicaLag"),
-			Values: []float64{*m.ReplicaLag},
+			name:        "Duplicate statistics",
+			definitions: []cloudwatch.MetricDefinition{{CloudWatchName: "DatabaseConnections", Statistics: []string{"Maximum", "Maximum"}, Name: "rds_database_connections"}},
+			expectError: true,
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("replicationslotdiskusage_%d", id)),
-			Label:  aws.String("ReplicationSlotDiskUsage"),
-			Values: []float64{*m.ReplicationSlotDiskUsage},
+			name:        "Missing CloudWatch metric name",
+			definitions: []cloudwatch.MetricDefinition{{Name: "rds_read_latency_seconds"}},
+			expectError: true,
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("swapusage_%d", id)),
-			Label:  aws.String("SwapUsage"),
-			Values: []float64{*m.SwapUsage},
+			name:        "Invalid Prometheus metric name",
+			definitions: []cloudwatch.MetricDefinition{{CloudWatchName: "ReadLatency", Name: "rds-read-latency"}},
+			expectError: true,
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("transactionlogsdiskusage_%d", id)),
-			Label:  aws.String("TransactionLogsDiskUsage"),
-			Values: []float64{*m.TransactionLogsDiskUsage},
+			name: "Duplicate Prometheus metric name",
+			definitions: []cloudwatch.MetricDefinition{
+				{CloudWatchName: "ReadLatency", Name: "rds_latency_seconds"},
+				{CloudWatchName: "WriteLatency", Name: "rds_latency_seconds"},
+			},
+			expectError: true,
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("writeiops_%d", id)),
-			Label:  aws.String("WriteIOPS"),
-			Values: []float64{*m.WriteIOPS},
+			name:        "Unknown statistic",
+			definitions: []cloudwatch.MetricDefinition{{CloudWatchName: "ReadLatency", Statistic: "Median", Name: "rds_read_latency_seconds"}},
+			expectError: true,
 		},
 		{
-			Id:     aws.String(fmt.Sprintf("writethroughput_%d", id)),
-			Label:  aws.String("WriteThroughput"),
-			Values: []float64{*m.WriteThroughput},
+			name:        "Sub-minute period",
+			definitions: []cloudwatch.MetricDefinition{{CloudWatchName: "ReadLatency", Period: 30 * time.Second, Name: "rds_read_latency_seconds"}},
+			expectError: true,
 		},
 	}
 
-	return metrics
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := cloudwatch.ValidateMetricDefinitions(tc.definitions)
+			if tc.expectError {
+				require.Error(t, err, "ValidateMetricDefinitions must fail")
+
+				return
+			}
+
+			require.NoError(t, err, "ValidateMetricDefinitions must succeed")
+		})
+	}
 }
 
-func TestGetDBInstanceTypeInformation(t *testing.T) {
-	instancesName := []string{}
-	data := []aws_cloudwatch_types.MetricDataResult{}
+func TestMetricPackDefinitions(t *testing.T) {
+	assert.Len(t, cloudwatch.DefaultMetricDefinitions(), 21, "Metric packs must not be collected by default")
 
-	// Generate instances metrics
-	instances := make(map[string]cloudwatch.RdsMetrics)
-	instances["db1"] = db1ExpecteRdsMetrics
-	instances["db2"] = db2ExpecteRdsMetrics
+	definitions, err := cloudwatch.MetricPackDefinitions(cloudwatch.MetricPacks())
+	require.NoError(t, err, "MetricPackDefinitions must succeed")
 
-	// Generate Cloudwatch API output metrics
-	i := 0
+	err = cloudwatch.ValidateMetricDefinitions(append(cloudwatch.DefaultMetricDefinitions(), definitions...))
+	require.NoError(t, err, "Metric packs must be valid with default definitions")
 
-	for id := range instances {
-		instancesName = append(instancesName, id)
-		instancesMetrics := generateMockedMetricsForInstance(i, instances[id])
+	definitions, err = cloudwatch.MetricPackDefinitions([]string{cloudwatch.AuroraMetricPack})
+	require.NoError(t, err, "MetricPackDefinitions must succeed")
 
-		data = append(data, instancesMetrics...)
-		i++
+	for _, definition := range definitions {
+		assert.Equal(t, []string{"aurora"}, definition.Engines, "Aurora metrics must only be queried for Aurora instances")
 	}
 
-	client := cloudwatch_mock.CloudwatchClient{Metrics: data}
-	fetcher := cloudwatch.NewRDSFetcher(client, slog.Logger{})
-	result, err := fetcher.GetRDSInstanceMetrics(instancesName)
+	_, err = cloudwatch.MetricPackDefinitions([]string{"unknown"})
+	require.Error(t, err, "MetricPackDefinitions must fail on unknown packs")
+}
 
-	require.NoError(t, err, "GetRDSInstanceMetrics must succeed")
-	assert.Equal(t, float64(1), fetcher.GetStatistics().CloudWatchAPICall, "One call to Cloudwatch API")
-
-	for id, value := range instances {
-		assert.Equal(t, value.DatabaseConnections, result.Instances[id].DatabaseConnections, "DatabaseConnections mismatch")
-		assert.Equal(t, value.CPUUtilization, result.Instances[id].CPUUtilization, "CPU utilization mismatch")
-		assert.Equal(t, value.DBLoad, result.Instances[id].DBLoad, "DBLoad mismatch")
-		assert.Equal(t, value.DBLoadCPU, result.Instances[id].DBLoadCPU, "DBLoadCPU mismatch")
-		assert.Equal(t, value.DBLoadNonCPU, result.Instances[id].DBLoadNonCPU, "DBLoadNonCPU mismatch")
-		assert.Equal(t, value.DatabaseConnections, result.Instances[id].DatabaseConnections, "DatabaseConnections mismatch")
-		assert.Equal(t, value.FreeStorageSpace, result.Instances[id].FreeStorageSpace, "FreeStorageSpace mismatch")
-		assert.Equal(t, value.FreeableMemory, result.Instances[id].FreeableMemory, "FreeableMemory mismatch")
-		assert.Equal(t, value.MaximumUsedTransactionIDs, result.Instances[id].MaximumUsedTransactionIDs, "MaximumUsedTransactionIDs mismatch")
-		assert.Equal(t, value.ReadIOPS, result.Instances[id].ReadIOPS, "ReadIOPS mismatch")
-		assert.Equal(t, value.ReadThroughput, result.Instances[id].ReadThroughput, "ReadThroughput mismatch")
-		assert.Equal(t, value.ReplicaLag, result.Instances[id].ReplicaLag, "ReplicaLag mismatch")
-		assert.Equal(t, value.ReplicationSlotDiskUsage, result.Instances[id].ReplicationSlotDiskUsage, "ReplicationSlotDiskUsage mismatch")
-		assert.Equal(t, value.SwapUsage, result.Instances[id].SwapUsage, "SwapUsage mismatch")
-		assert.Equal(t, value.TransactionLogsDiskUsage, result.Instances[id].TransactionLogsDiskUsage, "TransactionLogsDiskUsage mismatch")
-		assert.Equal(t, value.WriteIOPS, result.Instances[id].WriteIOPS, "WriteIOPS mismatch")
-		assert.Equal(t, value.WriteThroughput, result.Instances[id].WriteThroughput, "WriteThroughput mismatch")
+func TestMetricDefinitionMatches(t *testing.T) {
+	gp2Instance := cloudwatch.Resource{Identifier: "db1", Engine: "postgres", InstanceClass: "db.t3.small", StorageType: "gp2", BurstableEBS: true}
+	gp3Instance := cloudwatch.Resource{Identifier: "db2", Engine: "mysql", InstanceClass: "db.r6g.16xlarge", StorageType: "gp3"}
+
+	testCases := []struct {
+		name       string
+		definition cloudwatch.MetricDefinition
+		resource   cloudwatch.Resource
+		expected   bool
+	}{
+		{"No filter", cloudwatch.MetricDefinition{}, gp3Instance, true},
+		{"Matching engine", cloudwatch.MetricDefinition{Engines: []string{"aurora", "postgres"}}, gp2Instance, true},
+		{"Other engine", cloudwatch.MetricDefinition{Engines: []string{"aurora"}}, gp2Instance, false},
+		{"Matching instance class", cloudwatch.MetricDefinition{InstanceClasses: []string{"db.t"}}, gp2Instance, true},
+		{"Other instance class", cloudwatch.MetricDefinition{InstanceClasses: []string{"db.t"}}, gp3Instance, false},
+		{"Matching storage type", cloudwatch.MetricDefinition{StorageTypes: []string{"gp2"}}, gp2Instance, true},
+		{"Other storage type", cloudwatch.MetricDefinition{StorageTypes: []string{"gp2"}}, gp3Instance, false},
+		{"Burstable EBS", cloudwatch.MetricDefinition{BurstableEBS: true}, gp2Instance, true},
+		{"Non burstable EBS", cloudwatch.MetricDefinition{BurstableEBS: true}, gp3Instance, false},
+		{"All filters must match", cloudwatch.MetricDefinition{Engines: []string{"postgres"}, StorageTypes: []string{"gp3"}}, gp2Instance, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.definition.Matches(tc.resource))
+		})
 	}
 }
@@ -8,13 +8,31 @@ import (
 )
 
 type Statistics struct {
-	CloudWatchAPICall float64
+	IncompleteResults float64 // Queries without complete data (partial data, internal error or failed request)
+}
+
+// Resource is an RDS instance, cluster or proxy to collect CloudWatch metrics for
+type Resource struct {
+	Identifier    string
+	Dimensions    []Dimension // CloudWatch dimensions of the resource, when empty the resource is identified by its identifier
+	Engine        string
+	InstanceClass string
+	StorageType   string
+	BurstableEBS  bool // Instance class can burst over its EBS baseline bandwidth
+}
+
+// Dimension is a CloudWatch dimension of a resource
+type Dimension struct {
+	Name  string
+	Value string
 }
 
 type CloudWatchMetricRequest struct {
 	Query        aws_cloudwatch_types.MetricDataQuery
 	Dbidentifier string
 	MetricName   string
+	Definition   MetricDefinition
+	Statistic    string
 }
 
 type CloudWatchClient interface {
@@ -2,8 +2,10 @@ package cloudwatch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,27 +19,63 @@ import (
 
 var tracer = otel.Tracer("github/qonto/prometheus-rds-exporter/internal/app/cloudwatch")
 
+var errUnknownMetric = errors.New("unknown metric")
+
+// AllocatedStorageResource is the AWS/Usage resource of the storage allocated to DB instances, reported in gigabytes
+const AllocatedStorageResource = "AllocatedStorage"
+
+// UsageResources are the RDS resources published in the AWS/Usage namespace
+// See https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/monitoring-cloudwatch.html#rds-metrics-usage
+var UsageResources = []string{
+	AllocatedStorageResource,
+	"DBClusterParameterGroups",
+	"DBClusters",
+	"DBInstances",
+	"DBParameterGroups",
+	"DBSecurityGroups",
+	"DBSubnetGroups",
+	"EventSubscriptions",
+	"ManualClusterSnapshots",
+	"ManualSnapshots",
+	"OptionGroups",
+	"ReadReplicasPerMaster", // Highest number of read replicas of a source DB instance
+	"ReservedDBInstances",
+}
+
 type UsageMetrics struct {
 	AllocatedStorage    float64
 	DBInstances         float64
 	ManualSnapshots     float64
 	ReservedDBInstances float64
+	Resources           map[string]float64 // AWS/Usage resource => usage, allocated storage is in bytes
 }
 
 func (u *UsageMetrics) Update(field string, value float64) error {
+	if !slices.Contains(UsageResources, field) {
+		return fmt.Errorf("can't process %s metrics: %w", field, errUnknownMetric)
+	}
+
+	if field == AllocatedStorageResource {
+		value = converter.GigaBytesToBytes(value)
+	}
+
 	switch field {
-	case "AllocatedStorage":
-		u.AllocatedStorage = converter.GigaBytesToBytes(value)
+	case AllocatedStorageResource:
+		u.AllocatedStorage = value
 	case "DBInstances":
 		u.DBInstances = value
 	case "ManualSnapshots":
 		u.ManualSnapshots = value
 	case "ReservedDBInstances":
 		u.ReservedDBInstances = value
-	default:
-		return fmt.Errorf("can't process %s metrics: %w", field, errUnknownMetric)
 	}
 
+	if u.Resources == nil {
+		u.Resources = make(map[string]float64, len(UsageResources))
+	}
+
+	u.Resources[field] = value
+
 	return nil
 }
 
@@ -47,7 +85,8 @@ func generateCloudWatchDataQueriesForUsage(service string, metricsName []string)
 	for i, metricName := range metricsName {
 		id := aws.String(fmt.Sprintf("%s_%d", strings.ToLower(metricName), i))
 		query := &aws_cloudwatch_types.MetricDataQuery{
-			Id: id,
+			Id:    id,
+			Label: aws.String(metricName), // Results are matched to resources by label
 			MetricStat: &aws_cloudwatch_types.MetricStat{
 				Metric: &aws_cloudwatch_types.Metric{
 					Namespace:  aws.String("AWS/Usage"),
@@ -87,18 +126,13 @@ func generateCloudWatchDataQueriesForUsage(service string, metricsName []string)
 }
 
 func generateCloudWatchQueriesForUsage() *aws_cloudwatch.GetMetricDataInput {
-	metricsName := []string{
-		"AllocatedStorage",
-		"DBInstances",
-		"ManualSnapshots",
-	}
-
 	cloudwatchDataQueries := []aws_cloudwatch_types.MetricDataQuery{}
-	queries := generateCloudWatchDataQueriesForUsage("RDS", metricsName)
+	queries := generateCloudWatchDataQueriesForUsage("RDS", UsageResources)
 
 	for _, usageQuery := range queries {
 		query := aws_cloudwatch_types.MetricDataQuery{
-			Id: usageQuery.Query.Id,
+			Id:    usageQuery.Query.Id,
+			Label: usageQuery.Query.Label,
 			MetricStat: &aws_cloudwatch_types.MetricStat{
 				Metric: usageQuery.Query.MetricStat.Metric,
 				Stat:   aws.String("Average"),
@@ -125,28 +159,21 @@ func NewUsageFetcher(ctx context.Context, client CloudWatchClient, logger slog.L
 }
 
 type usageFetcher struct {
-	ctx        context.Context
-	client     CloudWatchClient
-	statistics Statistics
-	logger     *slog.Logger
-}
-
-func (u *usageFetcher) GetStatistics() Statistics {
-	return u.statistics
+	ctx    context.Context
+	client CloudWatchClient
+	logger *slog.Logger
 }
 
 // GetUsageMetrics returns RDS service usages metrics
 func (u *usageFetcher) GetUsageMetrics() (UsageMetrics, error) {
-	_, span := tracer.Start(u.ctx, "collect-usage")
+	ctx, span := tracer.Start(u.ctx, "collect-usage")
 	defer span.End()
 
 	metrics := UsageMetrics{}
 
 	query := generateCloudWatchQueriesForUsage()
 
-	resp, err := u.client.GetMetricData(context.TODO(), query)
-	u.statistics.CloudWatchAPICall++
-
+	resp, err := u.client.GetMetricData(ctx, query)
 	if err != nil {
 		return metrics, fmt.Errorf("error calling GetMetricData: %w", err)
 	}
@@ -52,6 +52,37 @@ func TestGetUsageMetrics(t *testing.T) {
 	assert.Equal(t, expected.DBInstances, result.DBInstances, "DB instances count mismatch")
 	assert.Equal(t, expected.ManualSnapshots, result.ManualSnapshots, "Manual snapshots mismatch")
 	assert.Equal(t, expected.ReservedDBInstances, result.ReservedDBInstances, "Reserved DB instances mismatch")
+	assert.Equal(t, converter.GigaBytesToBytes(expected.AllocatedStorage), result.Resources["AllocatedStorage"], "Resources must contain allocated storage in bytes")
+	assert.Equal(t, expected.ReservedDBInstances, result.Resources["ReservedDBInstances"], "Resources must contain reserved DB instances")
+}
+
+func TestGetUsageMetricsOfAllResources(t *testing.T) {
+	var queries []aws_cloudwatch_types.MetricDataQuery
+
+	client := cloudwatch_mock.CloudwatchClient{
+		Metrics: []aws_cloudwatch_types.MetricDataResult{
+			{
+				Label:  aws.String("DBClusters"),
+				Values: []float64{5},
+			},
+			{
+				Label:  aws.String("ReadReplicasPerMaster"),
+				Values: []float64{2},
+			},
+		},
+		Queries: &queries,
+	}
+
+	fetcher := cloudwatch.NewUsageFetcher(context.TODO(), client, slog.Logger{})
+	result, err := fetcher.GetUsageMetrics()
+
+	require.NoError(t, err, "GetUsageMetrics must succeed")
+	assert.Equal(t, map[string]float64{"DBClusters": 5, "ReadReplicasPerMaster": 2}, result.Resources, "Resources mismatch")
+
+	labels := make([]string, 0, len(queries))
+	for _, query := range queries {
+		labels = append(labels, aws.ToString(query.Label))
+	}
 
-	assert.Equal(t, float64(1), fetcher.GetStatistics().CloudWatchAPICall, "One call to Cloudwatch API")
+	assert.ElementsMatch(t, cloudwatch.UsageResources, labels, "Usage of every RDS resource must be requested")
 }
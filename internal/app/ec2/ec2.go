@@ -28,16 +28,14 @@ type EC2InstanceMetrics struct {
 	Memory                   int64
 	Vcpu                     int32
 	BaselineNetworkBandwidth float64
+	BurstablePerformance     bool // CPU performance can burst over baseline with CPU credits
+	BurstableEBS             bool // EBS bandwidth can burst over baseline with I/O and throughput credits
 }
 
 type Metrics struct {
 	Instances map[string]EC2InstanceMetrics
 }
 
-type Statistics struct {
-	EC2ApiCall float64
-}
-
 type EC2Client interface {
 	DescribeInstanceTypes(ctx context.Context, input *aws_ec2.DescribeInstanceTypesInput, fn ...func(*aws_ec2.Options)) (*aws_ec2.DescribeInstanceTypesOutput, error)
 }
@@ -50,13 +48,8 @@ func NewFetcher(context context.Context, client EC2Client) *EC2Fetcher {
 }
 
 type EC2Fetcher struct {
-	ctx        context.Context
-	client     EC2Client
-	statistics Statistics
-}
-
-func (e *EC2Fetcher) GetStatistics() Statistics {
-	return e.statistics
+	ctx    context.Context
+	client EC2Client
 }
 
 // GetDBInstanceTypeInformation returns information about specified AWS EC2 instance types
@@ -68,7 +61,7 @@ func (e *EC2Fetcher) GetDBInstanceTypeInformation(instanceTypes []string) (Metri
 	metrics := make(map[string]EC2InstanceMetrics)
 
 	for _, instances := range chunkBy(instanceTypes, maxInstanceTypesPerEC2APIRequest) {
-		_, instanceTypeSpan := tracer.Start(ctx, "collect-ec2-instance-types-metrics")
+		instanceTypeCtx, instanceTypeSpan := tracer.Start(ctx, "collect-ec2-instance-types-metrics")
 		defer instanceTypeSpan.End()
 
 		instanceTypeSpan.SetAttributes(trace.AWSInstanceTypesCount(int64(len(instances))))
@@ -81,7 +74,7 @@ func (e *EC2Fetcher) GetDBInstanceTypeInformation(instanceTypes []string) (Metri
 
 		input := &aws_ec2.DescribeInstanceTypesInput{InstanceTypes: instanceTypesToFetch}
 
-		resp, err := e.client.DescribeInstanceTypes(context.TODO(), input)
+		resp, err := e.client.DescribeInstanceTypes(instanceTypeCtx, input)
 		if err != nil {
 			instanceTypeSpan.SetStatus(codes.Error, "can't fetch describe instance types")
 			instanceTypeSpan.RecordError(err)
@@ -89,8 +82,6 @@ func (e *EC2Fetcher) GetDBInstanceTypeInformation(instanceTypes []string) (Metri
 			return Metrics{}, fmt.Errorf("can't fetch describe instance types: %w", err)
 		}
 
-		e.statistics.EC2ApiCall++
-
 		for _, i := range resp.InstanceTypes {
 			instanceMetrics := EC2InstanceMetrics{}
 
@@ -102,12 +93,17 @@ func (e *EC2Fetcher) GetDBInstanceTypeInformation(instanceTypes []string) (Metri
 				instanceMetrics.Memory = converter.MegaBytesToBytes(aws.ToInt64(i.MemoryInfo.SizeInMiB))
 			}
 
+			instanceMetrics.BurstablePerformance = aws.ToBool(i.BurstablePerformanceSupported)
+
 			if i.EbsInfo != nil && i.EbsInfo.EbsOptimizedInfo != nil {
 				instanceMetrics.BaselineIOPS = aws.ToInt32(i.EbsInfo.EbsOptimizedInfo.BaselineIops)
 				instanceMetrics.BaselineThroughput = converter.MegaBytesToBytes(aws.ToFloat64(i.EbsInfo.EbsOptimizedInfo.BaselineThroughputInMBps))
 
 				instanceMetrics.MaximumIops = aws.ToInt32(i.EbsInfo.EbsOptimizedInfo.MaximumIops)
 				instanceMetrics.MaximumThroughput = converter.MegaBytesToBytes(aws.ToFloat64(i.EbsInfo.EbsOptimizedInfo.MaximumThroughputInMBps))
+
+				// Instance classes with a maximum EBS bandwidth over baseline use EBS credits
+				instanceMetrics.BurstableEBS = instanceMetrics.MaximumThroughput > instanceMetrics.BaselineThroughput
 			}
 
 			if i.NetworkInfo != nil && i.NetworkInfo.NetworkCards != nil && len(i.NetworkInfo.NetworkCards) > 0 {
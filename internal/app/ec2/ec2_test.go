@@ -23,6 +23,7 @@ func TestGetDBInstanceTypeInformation(t *testing.T) {
 		maximumIops        int32
 		baselineThroughput float64
 		maximumThroughput  float64
+		burstableEBS       bool
 	}{
 		{
 			baselineIops:       mock.InstanceT3Large.BaselineIOPS,
@@ -32,6 +33,7 @@ func TestGetDBInstanceTypeInformation(t *testing.T) {
 			memory:             converter.MegaBytesToBytes(mock.InstanceT3Large.Memory),
 			maximumIops:        mock.InstanceT3Large.MaximumIops,
 			maximumThroughput:  converter.MegaBytesToBytes(mock.InstanceT3Large.MaximumThroughput),
+			burstableEBS:       true,
 		},
 		{
 			baselineIops:       mock.InstanceT3Small.BaselineIOPS,
@@ -41,6 +43,7 @@ func TestGetDBInstanceTypeInformation(t *testing.T) {
 			memory:             converter.MegaBytesToBytes(mock.InstanceT3Small.Memory),
 			maximumIops:        mock.InstanceT3Small.MaximumIops,
 			maximumThroughput:  converter.MegaBytesToBytes(mock.InstanceT3Small.MaximumThroughput),
+			burstableEBS:       true,
 		},
 		{
 			baselineIops:       0, // Don't have Maximum IOPS for non EBS optimized instances
@@ -52,7 +55,6 @@ func TestGetDBInstanceTypeInformation(t *testing.T) {
 			maximumThroughput:  0, // Don't have Maximum throughput for non EBS optimized instances
 		},
 	}
-	expectedAPICalls := float64(1)
 
 	instanceTypes := make([]string, len(testCases))
 	for i, instance := range testCases {
@@ -63,7 +65,6 @@ func TestGetDBInstanceTypeInformation(t *testing.T) {
 	result, err := fetcher.GetDBInstanceTypeInformation(instanceTypes)
 
 	require.NoError(t, err, "GetDBInstanceTypeInformation must succeed")
-	assert.Equal(t, expectedAPICalls, fetcher.GetStatistics().EC2ApiCall, "EC2 API calls don't match")
 
 	for _, tc := range testCases {
 		testName := "Test " + tc.instanceType
@@ -76,6 +77,8 @@ func TestGetDBInstanceTypeInformation(t *testing.T) {
 			assert.Equal(t, tc.baselineThroughput, instance.BaselineThroughput, "Baseline throughput don't match")
 			assert.Equal(t, tc.maximumIops, instance.MaximumIops, "Maximum IOPS don't match")
 			assert.Equal(t, tc.maximumThroughput, instance.MaximumThroughput, "Maximum throughput don't match")
+			assert.True(t, instance.BurstablePerformance, "Burstable performance don't match")
+			assert.Equal(t, tc.burstableEBS, instance.BurstableEBS, "Burstable EBS don't match")
 		})
 	}
 }
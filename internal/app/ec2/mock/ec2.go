@@ -11,28 +11,33 @@ import (
 
 //nolint:golint,mnd
 var InstanceT3Large = ec2.EC2InstanceMetrics{
-	BaselineIOPS:       4000,
-	BaselineThroughput: 86.88,
-	MaximumIops:        15700,
-	MaximumThroughput:  347.5,
-	Memory:             8,
-	Vcpu:               2,
+	BaselineIOPS:         4000,
+	BaselineThroughput:   86.88,
+	MaximumIops:          15700,
+	MaximumThroughput:    347.5,
+	Memory:               8,
+	Vcpu:                 2,
+	BurstablePerformance: true,
+	BurstableEBS:         true,
 }
 
 //nolint:golint,mnd
 var InstanceT3Small = ec2.EC2InstanceMetrics{
-	BaselineIOPS:       1000,
-	BaselineThroughput: 21.75,
-	MaximumIops:        11800,
-	MaximumThroughput:  260.62,
-	Memory:             2,
-	Vcpu:               2,
+	BaselineIOPS:         1000,
+	BaselineThroughput:   21.75,
+	MaximumIops:          11800,
+	MaximumThroughput:    260.62,
+	Memory:               2,
+	Vcpu:                 2,
+	BurstablePerformance: true,
+	BurstableEBS:         true,
 }
 
 //nolint:golint,mnd
 var InstanceT2Small = ec2.EC2InstanceMetrics{
-	Memory: 2,
-	Vcpu:   1,
+	Memory:               2,
+	Vcpu:                 1,
+	BurstablePerformance: true,
 }
 
 type EC2Client struct{}
@@ -45,9 +50,10 @@ func (m EC2Client) DescribeInstanceTypes(ctx context.Context, input *aws_ec2.Des
 		switch instanceType {
 		case "t3.large":
 			instances = append(instances, aws_ec2_types.InstanceTypeInfo{
-				InstanceType: instanceType,
-				VCpuInfo:     &aws_ec2_types.VCpuInfo{DefaultVCpus: &InstanceT3Large.Vcpu},
-				MemoryInfo:   &aws_ec2_types.MemoryInfo{SizeInMiB: &InstanceT3Large.Memory},
+				InstanceType:                  instanceType,
+				BurstablePerformanceSupported: &InstanceT3Large.BurstablePerformance,
+				VCpuInfo:                      &aws_ec2_types.VCpuInfo{DefaultVCpus: &InstanceT3Large.Vcpu},
+				MemoryInfo:                    &aws_ec2_types.MemoryInfo{SizeInMiB: &InstanceT3Large.Memory},
 				EbsInfo: &aws_ec2_types.EbsInfo{EbsOptimizedInfo: &aws_ec2_types.EbsOptimizedInfo{
 					BaselineIops:             &InstanceT3Large.BaselineIOPS,
 					BaselineThroughputInMBps: &InstanceT3Large.BaselineThroughput,
@@ -57,9 +63,10 @@ func (m EC2Client) DescribeInstanceTypes(ctx context.Context, input *aws_ec2.Des
 			})
 		case "t3.small":
 			instances = append(instances, aws_ec2_types.InstanceTypeInfo{
-				InstanceType: instanceType,
-				VCpuInfo:     &aws_ec2_types.VCpuInfo{DefaultVCpus: &InstanceT3Small.Vcpu},
-				MemoryInfo:   &aws_ec2_types.MemoryInfo{SizeInMiB: &InstanceT3Small.Memory},
+				InstanceType:                  instanceType,
+				BurstablePerformanceSupported: &InstanceT3Small.BurstablePerformance,
+				VCpuInfo:                      &aws_ec2_types.VCpuInfo{DefaultVCpus: &InstanceT3Small.Vcpu},
+				MemoryInfo:                    &aws_ec2_types.MemoryInfo{SizeInMiB: &InstanceT3Small.Memory},
 				EbsInfo: &aws_ec2_types.EbsInfo{EbsOptimizedInfo: &aws_ec2_types.EbsOptimizedInfo{
 					BaselineIops:             &InstanceT3Small.BaselineIOPS,
 					BaselineThroughputInMBps: &InstanceT3Small.BaselineThroughput,
@@ -69,9 +76,10 @@ func (m EC2Client) DescribeInstanceTypes(ctx context.Context, input *aws_ec2.Des
 			})
 		case "t2.small":
 			instances = append(instances, aws_ec2_types.InstanceTypeInfo{
-				InstanceType: instanceType,
-				VCpuInfo:     &aws_ec2_types.VCpuInfo{DefaultVCpus: &InstanceT2Small.Vcpu},
-				MemoryInfo:   &aws_ec2_types.MemoryInfo{SizeInMiB: &InstanceT2Small.Memory},
+				InstanceType:                  instanceType,
+				BurstablePerformanceSupported: &InstanceT2Small.BurstablePerformance,
+				VCpuInfo:                      &aws_ec2_types.VCpuInfo{DefaultVCpus: &InstanceT2Small.Vcpu},
+				MemoryInfo:                    &aws_ec2_types.MemoryInfo{SizeInMiB: &InstanceT2Small.Memory},
 			})
 		}
 	}
@@ -0,0 +1,12 @@
+package enhancedmonitoring
+
+import (
+	"context"
+
+	aws_cloudwatchlogs "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// Client is the subset of the CloudWatch Logs API used by the exporter
+type Client interface {
+	GetLogEvents(context.Context, *aws_cloudwatchlogs.GetLogEventsInput, ...func(*aws_cloudwatchlogs.Options)) (*aws_cloudwatchlogs.GetLogEventsOutput, error)
+}
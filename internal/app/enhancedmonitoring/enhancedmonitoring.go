@@ -0,0 +1,368 @@
+// Package enhancedmonitoring implements methods to retrieve RDS Enhanced Monitoring OS metrics
+package enhancedmonitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_cloudwatchlogs "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	converter "github.com/qonto/prometheus-rds-exporter/internal/app/unit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var tracer = otel.Tracer("github/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring")
+
+// LogGroupName is the CloudWatch Logs group where Enhanced Monitoring publishes OS metrics, one log stream per DbiResourceId
+const LogGroupName = "RDSOSMetrics"
+
+const millisecondsPerSecond = 1000
+
+// AuroraStorageDevice is the device label of Aurora storage I/O statistics, reported without device
+const AuroraStorageDevice = "aurora-storage"
+
+// Memory types exported by Enhanced Monitoring (kilobytes) => label
+var memoryTypes = map[string]string{
+	"total":      "total",
+	"free":       "free",
+	"cached":     "cached",
+	"buffers":    "buffers",
+	"active":     "active",
+	"inactive":   "inactive",
+	"dirty":      "dirty",
+	"writeback":  "writeback",
+	"mapped":     "mapped",
+	"slab":       "slab",
+	"pageTables": "page_tables",
+}
+
+// Instance is an RDS instance with Enhanced Monitoring enabled
+type Instance struct {
+	DBIdentifier  string
+	DbiResourceID string
+}
+
+type NetworkMetrics struct {
+	Interface         string
+	ReceiveThroughput float64 // Bytes per second
+	SendThroughput    float64 // Bytes per second
+}
+
+// DiskIOMetrics are I/O statistics of a device
+// Statistics are nil when not reported by the engine (eg. Aurora storage doesn't report await, other devices don't report latencies)
+type DiskIOMetrics struct {
+	Device          string
+	ReadIOPS        *float64
+	WriteIOPS       *float64
+	ReadThroughput  *float64 // Bytes per second
+	WriteThroughput *float64 // Bytes per second
+	QueueLength     *float64
+	Await           *float64 // Seconds
+	ReadLatency     *float64 // Seconds
+	WriteLatency    *float64 // Seconds
+}
+
+type FileSystemMetrics struct {
+	Name       string
+	MountPoint string
+	Size       float64 // Bytes
+	Used       float64 // Bytes
+	MaxFiles   float64
+	UsedFiles  float64
+}
+
+// ProcessMetrics are the resources used by all processes with the same name
+type ProcessMetrics struct {
+	Memory         float64 // Resident set size in bytes
+	CPUUtilization float64 // Percent of the instance CPU
+}
+
+// OSMetrics are OS metrics of the latest Enhanced Monitoring document of an instance
+type OSMetrics struct {
+	Timestamp      time.Time
+	LoadAverage    map[string]float64 // Period (1m, 5m, 15m) => number of processes
+	CPUUtilization map[string]float64 // Mode => percent
+	Memory         map[string]float64 // Type => bytes
+	Tasks          map[string]float64 // State => number of tasks
+	Network        []NetworkMetrics
+	DiskIO         []DiskIOMetrics
+	FileSystems    []FileSystemMetrics
+	Processes      map[string]ProcessMetrics // Process name => resources
+}
+
+type Metrics struct {
+	Instances map[string]OSMetrics
+}
+
+// document is the JSON document published by Enhanced Monitoring
+// See https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_Monitoring-Available-OS-Metrics.html
+type document struct {
+	Timestamp         time.Time          `json:"timestamp"`
+	CPUUtilization    map[string]float64 `json:"cpuUtilization"`
+	LoadAverageMinute struct {
+		One     float64 `json:"one"`
+		Five    float64 `json:"five"`
+		Fifteen float64 `json:"fifteen"`
+	} `json:"loadAverageMinute"`
+	Memory  map[string]float64 `json:"memory"`
+	Tasks   map[string]float64 `json:"tasks"`
+	Network []struct {
+		Interface string  `json:"interface"`
+		Rx        float64 `json:"rx"`
+		Tx        float64 `json:"tx"`
+	} `json:"network"`
+	DiskIO []struct {
+		Device      string   `json:"device"`
+		ReadIOsPS   *float64 `json:"readIOsPS"`
+		WriteIOsPS  *float64 `json:"writeIOsPS"`
+		ReadKbPS    *float64 `json:"readKbPS"`
+		WriteKbPS   *float64 `json:"writeKbPS"`
+		AvgQueueLen *float64 `json:"avgQueueLen"`
+		Await       *float64 `json:"await"` // Milliseconds
+
+		// Aurora storage statistics
+		ReadThroughput  *float64 `json:"readThroughput"`  // Bytes per second
+		WriteThroughput *float64 `json:"writeThroughput"` // Bytes per second
+		DiskQueueDepth  *float64 `json:"diskQueueDepth"`
+		ReadLatency     *float64 `json:"readLatency"`  // Milliseconds
+		WriteLatency    *float64 `json:"writeLatency"` // Milliseconds
+	} `json:"diskIO"`
+	FileSys []struct {
+		Name       string  `json:"name"`
+		MountPoint string  `json:"mountPoint"`
+		Total      float64 `json:"total"` // Kilobytes
+		Used       float64 `json:"used"`  // Kilobytes
+		MaxFiles   float64 `json:"maxFiles"`
+		UsedFiles  float64 `json:"usedFiles"`
+	} `json:"fileSys"`
+	ProcessList []struct {
+		Name      string  `json:"name"`
+		RSS       float64 `json:"rss"` // Kilobytes
+		CPUUsedPc float64 `json:"cpuUsedPc"`
+	} `json:"processList"`
+}
+
+func NewFetcher(ctx context.Context, client Client, logger slog.Logger) *Fetcher {
+	return &Fetcher{
+		ctx:    ctx,
+		client: client,
+		logger: &logger,
+	}
+}
+
+type Fetcher struct {
+	ctx    context.Context
+	client Client
+	logger *slog.Logger
+}
+
+// GetInstancesMetrics returns OS metrics of the latest Enhanced Monitoring document of instances by dbidentifier
+// Instances without document yet are ignored. A failing instance doesn't prevent fetching other instances, errors are returned with fetched metrics
+func (f *Fetcher) GetInstancesMetrics(instances []Instance) (Metrics, error) {
+	ctx, span := tracer.Start(f.ctx, "collect-enhanced-monitoring-metrics")
+	defer span.End()
+
+	metrics := Metrics{Instances: make(map[string]OSMetrics, len(instances))}
+
+	var errs []error
+
+	for _, instance := range instances {
+		osMetrics, found, err := f.getInstanceMetrics(ctx, instance)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't get Enhanced Monitoring metrics of %s: %w", instance.DBIdentifier, err))
+
+			continue
+		}
+
+		if !found {
+			continue
+		}
+
+		metrics.Instances[instance.DBIdentifier] = osMetrics
+	}
+
+	span.SetAttributes(attribute.Int("qonto.prometheus_rds_exporter.instance_count", len(metrics.Instances)))
+
+	if len(errs) > 0 {
+		err := errors.Join(errs...)
+
+		span.SetStatus(codes.Error, "can't get Enhanced Monitoring metrics")
+		span.RecordError(err)
+
+		return metrics, err
+	}
+
+	span.SetStatus(codes.Ok, "metrics fetched")
+
+	return metrics, nil
+}
+
+// getInstanceMetrics returns OS metrics of the latest event of the instance log stream
+func (f *Fetcher) getInstanceMetrics(ctx context.Context, instance Instance) (OSMetrics, bool, error) {
+	input := &aws_cloudwatchlogs.GetLogEventsInput{
+		LogGroupName:  aws.String(LogGroupName),
+		LogStreamName: aws.String(instance.DbiResourceID),
+		Limit:         aws.Int32(1),
+		StartFromHead: aws.Bool(false), // Latest event
+	}
+
+	output, err := f.client.GetLogEvents(ctx, input)
+	if err != nil {
+		return OSMetrics{}, false, fmt.Errorf("can't get log events: %w", err)
+	}
+
+	if len(output.Events) == 0 {
+		return OSMetrics{}, false, nil
+	}
+
+	event := output.Events[len(output.Events)-1]
+
+	osMetrics, err := ParseDocument(aws.ToString(event.Message))
+	if err != nil {
+		return OSMetrics{}, false, err
+	}
+
+	// Documents without timestamp use the time of the log event
+	if osMetrics.Timestamp.IsZero() {
+		osMetrics.Timestamp = time.UnixMilli(aws.ToInt64(event.Timestamp))
+	}
+
+	return osMetrics, true, nil
+}
+
+// ParseDocument returns OS metrics of an Enhanced Monitoring JSON document
+func ParseDocument(message string) (OSMetrics, error) {
+	var doc document
+
+	err := json.Unmarshal([]byte(message), &doc)
+	if err != nil {
+		return OSMetrics{}, fmt.Errorf("can't decode Enhanced Monitoring document: %w", err)
+	}
+
+	metrics := OSMetrics{
+		Timestamp: doc.Timestamp,
+		LoadAverage: map[string]float64{
+			"1m":  doc.LoadAverageMinute.One,
+			"5m":  doc.LoadAverageMinute.Five,
+			"15m": doc.LoadAverageMinute.Fifteen,
+		},
+		CPUUtilization: make(map[string]float64, len(doc.CPUUtilization)),
+		Memory:         make(map[string]float64, len(memoryTypes)),
+		Tasks:          make(map[string]float64, len(doc.Tasks)),
+		Processes:      make(map[string]ProcessMetrics),
+	}
+
+	// Totals are the sum of other modes and states
+	for mode, value := range doc.CPUUtilization {
+		if mode != "total" {
+			metrics.CPUUtilization[mode] = value
+		}
+	}
+
+	for state, value := range doc.Tasks {
+		if state != "total" {
+			metrics.Tasks[state] = value
+		}
+	}
+
+	for key, memoryType := range memoryTypes {
+		if value, found := doc.Memory[key]; found {
+			metrics.Memory[memoryType] = converter.KiloBytesToBytes(value)
+		}
+	}
+
+	for _, network := range doc.Network {
+		metrics.Network = append(metrics.Network, NetworkMetrics{
+			Interface:         network.Interface,
+			ReceiveThroughput: network.Rx,
+			SendThroughput:    network.Tx,
+		})
+	}
+
+	// Aurora reports its storage I/O statistics without device, next to local devices
+	devices := make(map[string]bool, len(doc.DiskIO))
+
+	for _, disk := range doc.DiskIO {
+		device := disk.Device
+		if device == "" {
+			device = AuroraStorageDevice
+		}
+
+		// Duplicate devices would fail the scrape
+		if devices[device] {
+			continue
+		}
+
+		devices[device] = true
+
+		metrics.DiskIO = append(metrics.DiskIO, DiskIOMetrics{
+			Device:          device,
+			ReadIOPS:        disk.ReadIOsPS,
+			WriteIOPS:       disk.WriteIOsPS,
+			ReadThroughput:  firstValue(kiloBytesToBytes(disk.ReadKbPS), disk.ReadThroughput),
+			WriteThroughput: firstValue(kiloBytesToBytes(disk.WriteKbPS), disk.WriteThroughput),
+			QueueLength:     firstValue(disk.AvgQueueLen, disk.DiskQueueDepth),
+			Await:           millisecondsToSeconds(disk.Await),
+			ReadLatency:     millisecondsToSeconds(disk.ReadLatency),
+			WriteLatency:    millisecondsToSeconds(disk.WriteLatency),
+		})
+	}
+
+	for _, fileSystem := range doc.FileSys {
+		metrics.FileSystems = append(metrics.FileSystems, FileSystemMetrics{
+			Name:       fileSystem.Name,
+			MountPoint: fileSystem.MountPoint,
+			Size:       converter.KiloBytesToBytes(fileSystem.Total),
+			Used:       converter.KiloBytesToBytes(fileSystem.Used),
+			MaxFiles:   fileSystem.MaxFiles,
+			UsedFiles:  fileSystem.UsedFiles,
+		})
+	}
+
+	// Processes are aggregated by name to bound cardinality, the process list contains one entry per process or thread
+	for _, process := range doc.ProcessList {
+		processMetrics := metrics.Processes[process.Name]
+		processMetrics.Memory += converter.KiloBytesToBytes(process.RSS)
+		processMetrics.CPUUtilization += process.CPUUsedPc
+		metrics.Processes[process.Name] = processMetrics
+	}
+
+	return metrics, nil
+}
+
+// firstValue returns the first statistic reported by the engine
+func firstValue(values ...*float64) *float64 {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+
+	return nil
+}
+
+func kiloBytesToBytes(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+
+	bytes := converter.KiloBytesToBytes(*value)
+
+	return &bytes
+}
+
+func millisecondsToSeconds(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+
+	seconds := *value / millisecondsPerSecond
+
+	return &seconds
+}
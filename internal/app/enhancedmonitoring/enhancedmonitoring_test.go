@@ -0,0 +1,139 @@
+package enhancedmonitoring_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring"
+	mock "github.com/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocument(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	m, err := enhancedmonitoring.ParseDocument(mock.NewDocument("db-1", timestamp))
+	require.NoError(t, err, "ParseDocument must succeed")
+
+	assert.Equal(t, timestamp, m.Timestamp, "Timestamp mismatch")
+	assert.Equal(t, map[string]float64{"1m": 0.5, "5m": 0.25, "15m": 0.1}, m.LoadAverage, "Load average mismatch")
+
+	assert.NotContains(t, m.CPUUtilization, "total", "CPU total must be skipped")
+	assert.InDelta(t, 2.5, m.CPUUtilization["user"], 0, "CPU user mismatch")
+	assert.Len(t, m.CPUUtilization, 8, "CPU modes mismatch")
+
+	assert.InDelta(t, float64(8192*1024), m.Memory["total"], 0, "Memory must be converted to bytes")
+	assert.InDelta(t, float64(16*1024), m.Memory["page_tables"], 0, "Memory types must be snake case")
+	assert.NotContains(t, m.Memory, "hugePagesTotal", "Huge pages counts must be skipped")
+
+	assert.NotContains(t, m.Tasks, "total", "Tasks total must be skipped")
+	assert.InDelta(t, float64(2), m.Tasks["running"], 0, "Running tasks mismatch")
+
+	assert.Equal(t, []enhancedmonitoring.NetworkMetrics{{Interface: "eth0", ReceiveThroughput: 1500, SendThroughput: 3000}}, m.Network, "Network mismatch")
+
+	require.Len(t, m.DiskIO, 1, "Disk I/O mismatch")
+	disk := m.DiskIO[0]
+	assert.Equal(t, "rdsdev", disk.Device, "Device mismatch")
+	assert.InDelta(t, float64(10), *disk.ReadIOPS, 0, "Read IOPS mismatch")
+	assert.InDelta(t, float64(8*1024), *disk.WriteThroughput, 0, "Write throughput must be converted to bytes")
+	assert.InDelta(t, 0.0025, *disk.Await, 0.000001, "Await must be converted to seconds")
+
+	assert.Equal(t, []enhancedmonitoring.FileSystemMetrics{{Name: "rdsfilesys", MountPoint: "/rdsdbdata", Size: 4096 * 1024, Used: 1024 * 1024, MaxFiles: 1000, UsedFiles: 100}}, m.FileSystems, "File systems mismatch")
+
+	expectedProcesses := map[string]enhancedmonitoring.ProcessMetrics{
+		"postgres":     {Memory: 300 * 1024, CPUUtilization: 2},
+		"OS processes": {Memory: 50 * 1024, CPUUtilization: 0.25},
+	}
+	assert.Equal(t, expectedProcesses, m.Processes, "Processes must be aggregated by name")
+}
+
+func TestParseAuroraDocument(t *testing.T) {
+	t.Parallel()
+
+	m, err := enhancedmonitoring.ParseDocument(mock.NewAuroraDocument("aurora-1", time.Now()))
+	require.NoError(t, err, "ParseDocument must succeed")
+
+	require.Len(t, m.DiskIO, 2, "Disk I/O mismatch")
+
+	storage := m.DiskIO[0]
+	assert.Equal(t, enhancedmonitoring.AuroraStorageDevice, storage.Device, "Aurora storage must have a device")
+	assert.InDelta(t, float64(4), *storage.ReadIOPS, 0, "Read IOPS mismatch")
+	assert.InDelta(t, float64(6), *storage.WriteIOPS, 0, "Write IOPS mismatch")
+	assert.InDelta(t, float64(2048), *storage.ReadThroughput, 0, "Aurora read throughput is in bytes")
+	assert.InDelta(t, float64(4096), *storage.WriteThroughput, 0, "Aurora write throughput is in bytes")
+	assert.InDelta(t, 0.2, *storage.QueueLength, 0, "Aurora disk queue depth mismatch")
+	assert.InDelta(t, 0.0005, *storage.ReadLatency, 0.000001, "Read latency must be converted to seconds")
+	assert.InDelta(t, 0.0015, *storage.WriteLatency, 0.000001, "Write latency must be converted to seconds")
+	assert.Nil(t, storage.Await, "Missing statistics must be nil")
+
+	device := m.DiskIO[1]
+	assert.Equal(t, "rdsdev", device.Device, "Device mismatch")
+	assert.InDelta(t, float64(8*1024), *device.WriteThroughput, 0, "Write throughput must be converted to bytes")
+	assert.Nil(t, device.ReadLatency, "Missing statistics must be nil")
+}
+
+func TestParseDocumentWithoutDeviceStatistics(t *testing.T) {
+	t.Parallel()
+
+	// Statistics missing from the document are not exported
+	m, err := enhancedmonitoring.ParseDocument(`{"diskIO": [{"readIOsPS": 5, "writeIOsPS": 3, "readLatency": 1.2}]}`)
+	require.NoError(t, err, "ParseDocument must succeed")
+
+	require.Len(t, m.DiskIO, 1, "Disk I/O mismatch")
+	assert.InDelta(t, float64(5), *m.DiskIO[0].ReadIOPS, 0, "Read IOPS mismatch")
+	assert.Nil(t, m.DiskIO[0].QueueLength, "Missing statistics must be nil")
+	assert.Nil(t, m.DiskIO[0].ReadThroughput, "Missing statistics must be nil")
+}
+
+func TestParseDocumentWithDuplicateDevices(t *testing.T) {
+	t.Parallel()
+
+	m, err := enhancedmonitoring.ParseDocument(`{"diskIO": [{"readIOsPS": 5}, {"readIOsPS": 3}, {"device": "rdsdev", "readIOsPS": 1}, {"device": "rdsdev", "readIOsPS": 2}]}`)
+	require.NoError(t, err, "ParseDocument must succeed")
+
+	require.Len(t, m.DiskIO, 2, "Duplicate devices must be skipped")
+	assert.Equal(t, enhancedmonitoring.AuroraStorageDevice, m.DiskIO[0].Device, "Device mismatch")
+	assert.InDelta(t, float64(5), *m.DiskIO[0].ReadIOPS, 0, "First entry of a device must be kept")
+	assert.Equal(t, "rdsdev", m.DiskIO[1].Device, "Device mismatch")
+	assert.InDelta(t, float64(1), *m.DiskIO[1].ReadIOPS, 0, "First entry of a device must be kept")
+}
+
+func TestParseInvalidDocument(t *testing.T) {
+	t.Parallel()
+
+	_, err := enhancedmonitoring.ParseDocument("not a JSON document")
+	require.ErrorContains(t, err, "can't decode Enhanced Monitoring document", "ParseDocument must fail")
+}
+
+func TestGetInstancesMetrics(t *testing.T) {
+	t.Parallel()
+
+	instance := newInstance("db-1", "db-RESOURCE1")
+	instanceWithoutDocument := newInstance("db-2", "db-RESOURCE2")
+	failingInstance := newInstance("db-3", "db-RESOURCE3")
+
+	client := mock.CloudWatchLogsClient{
+		Documents:     map[string]string{instance.DbiResourceID: mock.NewDocument(instance.DBIdentifier, time.Now())},
+		FailingStream: failingInstance.DbiResourceID,
+	}
+
+	fetcher := enhancedmonitoring.NewFetcher(context.TODO(), client, slog.Logger{})
+
+	metrics, err := fetcher.GetInstancesMetrics([]enhancedmonitoring.Instance{instance, instanceWithoutDocument, failingInstance})
+	require.ErrorIs(t, err, mock.ErrGetLogEvents, "GetInstancesMetrics must return instance errors")
+	assert.ErrorContains(t, err, "db-3", "Error must contain the failing instance")
+
+	assert.Contains(t, metrics.Instances, instance.DBIdentifier, "Instance with document must be returned")
+	assert.NotContains(t, metrics.Instances, instanceWithoutDocument.DBIdentifier, "Instance without document must be ignored")
+	assert.NotContains(t, metrics.Instances, failingInstance.DBIdentifier, "Failing instance must not be returned")
+
+}
+
+func newInstance(dbIdentifier string, dbiResourceID string) enhancedmonitoring.Instance {
+	return enhancedmonitoring.Instance{DBIdentifier: dbIdentifier, DbiResourceID: dbiResourceID}
+}
@@ -0,0 +1,86 @@
+// Package mocks contains mock for CloudWatch Logs client
+package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_cloudwatchlogs "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	aws_cloudwatchlogs_types "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring"
+)
+
+var ErrGetLogEvents = errors.New("GetLogEvents failed")
+
+type CloudWatchLogsClient struct {
+	Documents     map[string]string // Enhanced Monitoring document by log stream (DbiResourceId)
+	FailingStream string            // GetLogEvents fails for this log stream
+}
+
+func (m CloudWatchLogsClient) GetLogEvents(_ context.Context, input *aws_cloudwatchlogs.GetLogEventsInput, _ ...func(*aws_cloudwatchlogs.Options)) (*aws_cloudwatchlogs.GetLogEventsOutput, error) {
+	logStreamName := aws.ToString(input.LogStreamName)
+
+	if m.FailingStream != "" && logStreamName == m.FailingStream {
+		return nil, ErrGetLogEvents
+	}
+
+	output := &aws_cloudwatchlogs.GetLogEventsOutput{}
+
+	if document, found := m.Documents[logStreamName]; found && aws.ToString(input.LogGroupName) == enhancedmonitoring.LogGroupName {
+		output.Events = append(output.Events, aws_cloudwatchlogs_types.OutputLogEvent{Timestamp: aws.Int64(time.Now().UnixMilli()), Message: aws.String(document)})
+	}
+
+	return output, nil
+}
+
+// NewDocument returns an Enhanced Monitoring document of a PostgreSQL instance
+func NewDocument(instanceID string, timestamp time.Time) string {
+	return fmt.Sprintf(`{
+  "engine": "POSTGRES",
+  "instanceID": %q,
+  "timestamp": %q,
+  "version": 1,
+  "numVCPUs": 2,
+  "cpuUtilization": {"guest": 0, "irq": 0.01, "system": 1.5, "wait": 0.5, "idle": 95, "user": 2.5, "total": 5, "steal": 0.49, "nice": 0},
+  "loadAverageMinute": {"one": 0.5, "five": 0.25, "fifteen": 0.1},
+  "memory": {"writeback": 0, "hugePagesFree": 0, "cached": 2048, "free": 1024, "hugePagesTotal": 0, "inactive": 512, "pageTables": 16, "dirty": 8, "mapped": 256, "active": 4096, "total": 8192, "slab": 128, "buffers": 64},
+  "tasks": {"sleeping": 120, "zombie": 0, "running": 2, "stopped": 0, "total": 123, "blocked": 1},
+  "swap": {"cached": 0, "total": 0, "free": 0, "in": 0, "out": 0},
+  "network": [{"interface": "eth0", "rx": 1500, "tx": 3000}],
+  "diskIO": [{"writeKbPS": 8, "readIOsPS": 10, "await": 2.5, "readKbPS": 4, "util": 1, "avgQueueLen": 0.5, "tps": 12, "device": "rdsdev", "writeIOsPS": 2}],
+  "fileSys": [{"used": 1024, "name": "rdsfilesys", "usedFiles": 100, "maxFiles": 1000, "mountPoint": "/rdsdbdata", "total": 4096, "usedPercent": 25}],
+  "processList": [
+    {"vss": 1000, "name": "postgres", "tgid": 1, "parentID": 0, "memoryUsedPc": 1, "cpuUsedPc": 1.5, "id": 1, "rss": 100},
+    {"vss": 1000, "name": "postgres", "tgid": 2, "parentID": 1, "memoryUsedPc": 2, "cpuUsedPc": 0.5, "id": 2, "rss": 200},
+    {"vss": 500, "name": "OS processes", "tgid": 0, "parentID": 0, "memoryUsedPc": 1, "cpuUsedPc": 0.25, "id": 0, "rss": 50}
+  ]
+}`, instanceID, timestamp.UTC().Format(time.RFC3339))
+}
+
+// NewAuroraDocument returns an Enhanced Monitoring document of an Aurora PostgreSQL instance
+// Aurora reports its storage I/O statistics without device, next to the local device
+func NewAuroraDocument(instanceID string, timestamp time.Time) string {
+	return fmt.Sprintf(`{
+  "engine": "POSTGRES",
+  "instanceID": %q,
+  "timestamp": %q,
+  "version": 1,
+  "numVCPUs": 2,
+  "cpuUtilization": {"guest": 0, "irq": 0, "system": 1, "wait": 0.1, "idle": 97, "user": 1.5, "total": 3, "steal": 0.4, "nice": 0},
+  "loadAverageMinute": {"one": 0.2, "five": 0.1, "fifteen": 0.05},
+  "memory": {"writeback": 0, "cached": 2048, "free": 1024, "inactive": 512, "pageTables": 16, "dirty": 8, "mapped": 256, "active": 4096, "total": 8192, "slab": 128, "buffers": 64},
+  "tasks": {"sleeping": 100, "zombie": 0, "running": 1, "stopped": 0, "total": 101, "blocked": 0},
+  "network": [{"interface": "eth0", "rx": 1500, "tx": 3000}],
+  "diskIO": [
+    {"readLatency": 0.5, "writeLatency": 1.5, "writeThroughput": 4096, "readThroughput": 2048, "readIOsPS": 4, "diskQueueDepth": 0.2, "writeIOsPS": 6},
+    {"writeKbPS": 8, "readIOsPS": 1, "await": 2.5, "readKbPS": 4, "rrqmPS": 0, "util": 1, "avgQueueLen": 0.5, "tps": 3, "readKb": 40, "device": "rdsdev", "writeKb": 80, "avgReqSz": 4, "wrqmPS": 0, "writeIOsPS": 2}
+  ],
+  "fileSys": [{"used": 1024, "name": "rdsfilesys", "usedFiles": 100, "maxFiles": 1000, "mountPoint": "/rdsdbdata", "total": 4096, "usedPercent": 25}],
+  "processList": [
+    {"vss": 1000, "name": "aurora", "tgid": 1, "parentID": 0, "memoryUsedPc": 1, "cpuUsedPc": 1, "id": 1, "rss": 100}
+  ]
+}`, instanceID, timestamp.UTC().Format(time.RFC3339))
+}
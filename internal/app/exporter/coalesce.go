@@ -0,0 +1,94 @@
+package exporter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// coalescer shares the result of an in-flight call with concurrent callers
+// so simultaneous scrapes trigger a single collection of AWS APIs
+// The call is detached from callers' contexts, so a cancelled caller does not fail other callers
+type coalescer struct {
+	ctx     context.Context // lifetime of the collector, cancels the in-flight call on exporter shutdown (optional)
+	timeout time.Duration   // bounds the in-flight call, disabled when zero
+
+	mutex     sync.Mutex
+	inflight  *coalescedCall
+	coalesced float64
+}
+
+type coalescedCall struct {
+	done chan struct{}
+	err  error
+}
+
+// do calls fn, or waits for the result of the in-flight call if any
+// Each caller stops waiting when its own context is done, while the call keeps running for other callers
+func (c *coalescer) do(ctx context.Context, fn func(context.Context) error) error {
+	return c.call(ctx, fn, false)
+}
+
+// doScrape is do for scrapes, scrapes waiting for the in-flight call are counted by coalescedCount
+func (c *coalescer) doScrape(ctx context.Context, fn func(context.Context) error) error {
+	return c.call(ctx, fn, true)
+}
+
+func (c *coalescer) call(ctx context.Context, fn func(context.Context) error, scrape bool) error {
+	c.mutex.Lock()
+
+	call := c.inflight
+	if call != nil {
+		if scrape {
+			c.coalesced++
+		}
+	} else {
+		call = &coalescedCall{done: make(chan struct{})}
+		c.inflight = call
+
+		go c.run(ctx, call, fn)
+	}
+
+	c.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *coalescer) run(ctx context.Context, call *coalescedCall, fn func(context.Context) error) {
+	// Keep values of the first caller context (eg. tracing) without its cancellation
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	if c.ctx != nil {
+		stop := context.AfterFunc(c.ctx, cancel)
+		defer stop()
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	call.err = fn(ctx)
+
+	c.mutex.Lock()
+	c.inflight = nil
+	c.mutex.Unlock()
+
+	close(call.done)
+}
+
+// coalescedCount returns the number of scrapes that waited for an in-flight call
+func (c *coalescer) coalescedCount() float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.coalesced
+}
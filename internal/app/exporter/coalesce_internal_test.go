@@ -0,0 +1,193 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalescer(t *testing.T) {
+	var (
+		c       coalescer
+		calls   atomic.Int32
+		wg      sync.WaitGroup
+		release = make(chan struct{})
+	)
+
+	expectedErr := errors.New("AWS API is unavailable")
+
+	fn := func(context.Context) error {
+		calls.Add(1)
+		<-release
+
+		return expectedErr
+	}
+
+	callers := 5
+	errs := make([]error, callers)
+
+	for i := range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs[i] = c.doScrape(context.TODO(), fn)
+		}()
+	}
+
+	// Wait for all callers to join the in-flight call before releasing it
+	assert.Eventually(t, func() bool {
+		return c.coalescedCount() == float64(callers-1)
+	}, time.Second, time.Millisecond, "concurrent callers should wait for the in-flight call")
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "should call function once")
+
+	for _, err := range errs {
+		require.ErrorIs(t, err, expectedErr, "should share the in-flight call result")
+	}
+
+	// Calls after the end of in-flight call are not coalesced
+	err := c.do(context.TODO(), func(context.Context) error { return nil })
+	require.NoError(t, err, "should call function again")
+	assert.Equal(t, float64(callers-1), c.coalescedCount(), "should not coalesce sequential calls")
+}
+
+func TestCoalescerContextCancellation(t *testing.T) {
+	var c coalescer
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	go func() {
+		_ = c.do(context.TODO(), func(context.Context) error {
+			close(started)
+			<-release
+
+			return nil
+		})
+	}()
+
+	<-started
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	err := c.do(ctx, func(context.Context) error { return nil })
+	require.ErrorIs(t, err, context.Canceled, "waiting caller should stop when its context is cancelled")
+
+	close(release)
+}
+
+func TestCoalescerIsDetachedFromFirstCaller(t *testing.T) {
+	var c coalescer
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	fn := func(ctx context.Context) error {
+		close(started)
+		<-release
+
+		return ctx.Err()
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.TODO())
+	firstErr := make(chan error)
+
+	go func() {
+		firstErr <- c.do(firstCtx, fn)
+	}()
+
+	<-started
+
+	secondErr := make(chan error)
+
+	go func() {
+		secondErr <- c.doScrape(context.TODO(), fn)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return c.coalescedCount() == 1
+	}, time.Second, time.Millisecond, "second caller should wait for the in-flight call")
+
+	cancelFirst()
+	require.ErrorIs(t, <-firstErr, context.Canceled, "first caller should stop when its context is cancelled")
+
+	close(release)
+	require.NoError(t, <-secondErr, "in-flight call should not be cancelled by the first caller")
+}
+
+func TestCoalescerTimeout(t *testing.T) {
+	c := coalescer{timeout: time.Millisecond}
+
+	err := c.do(context.TODO(), func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded, "in-flight call should be bounded by the timeout")
+}
+
+func TestCoalescerOnlyCountsScrapes(t *testing.T) {
+	var c coalescer
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_ = c.doScrape(context.TODO(), func(context.Context) error {
+			close(started)
+			<-release
+
+			return nil
+		})
+	}()
+
+	<-started
+
+	// Background and refresh endpoint calls waiting for the in-flight call are not scrapes
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	err := c.do(ctx, func(context.Context) error { return nil })
+	require.ErrorIs(t, err, context.DeadlineExceeded, "caller should wait for the in-flight call")
+	assert.Zero(t, c.coalescedCount(), "should not count calls that are not scrapes")
+
+	close(release)
+	<-done
+}
+
+func TestCoalescerIsCancelledOnShutdown(t *testing.T) {
+	ctx, shutdown := context.WithCancel(context.TODO())
+	c := coalescer{ctx: ctx}
+
+	started := make(chan struct{})
+	result := make(chan error)
+
+	go func() {
+		result <- c.do(context.TODO(), func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+	}()
+
+	<-started
+	shutdown()
+
+	require.ErrorIs(t, <-result, context.Canceled, "in-flight call should be cancelled on shutdown")
+}
@@ -233,7 +233,7 @@ func TestEngineSupport_EndToEndMetricCollection(t *testing.T) {
 				CollectUsages:          false,
 			}
 
-			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 			// Collect metrics
 			registry := prometheus.NewRegistry()
@@ -311,7 +311,7 @@ func TestEngineSupport_CacheExpirationAndRefresh(t *testing.T) {
 		CollectUsages:          false,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 	// First collection - should call API
 	registry1 := prometheus.NewRegistry()
@@ -403,7 +403,7 @@ func TestEngineSupport_APIErrorHandling(t *testing.T) {
 				CollectUsages:          false,
 			}
 
-			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 			// Get initial error count
 			initialStats := collector.GetStatistics()
@@ -588,7 +588,7 @@ func TestEngineSupport_ComplexAPIResponses(t *testing.T) {
 				CollectUsages:          false,
 			}
 
-			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 			// Collect metrics
 			registry := prometheus.NewRegistry()
@@ -56,12 +56,15 @@ func TestEngineSupport_MetricDescriptors(t *testing.T) {
 		CollectUsages:          false,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 	// Test that metric descriptors are properly registered
-	ch := make(chan *prometheus.Desc, 100)
-	collector.Describe(ch)
-	close(ch)
+	ch := make(chan *prometheus.Desc)
+
+	go func() {
+		collector.Describe(ch)
+		close(ch)
+	}()
 
 	var standardSupportDesc, extendedSupportDesc *prometheus.Desc
 	for desc := range ch {
@@ -137,7 +140,7 @@ func TestEngineSupport_PostgreSQLEngineFiltering(t *testing.T) {
 		CollectUsages:          false,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 	// Collect metrics
 	registry := prometheus.NewRegistry()
@@ -249,7 +252,7 @@ func TestEngineSupport_MetricEmission(t *testing.T) {
 		CollectUsages:          false,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 	// Test metric emission
 	registry := prometheus.NewRegistry()
@@ -426,7 +429,7 @@ func TestEngineSupport_ErrorScenarios(t *testing.T) {
 				CollectUsages:          false,
 			}
 
-			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+			collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 			// Collect metrics
 			registry := prometheus.NewRegistry()
@@ -521,7 +524,7 @@ func TestEngineSupport_NegativeValues(t *testing.T) {
 		CollectUsages:          false,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 	// Test metric emission with negative values
 	registry := prometheus.NewRegistry()
@@ -597,7 +600,7 @@ func TestEngineSupport_MetricsDisabled(t *testing.T) {
 		CollectUsages:          false,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
 	// Collect metrics
 	registry := prometheus.NewRegistry()
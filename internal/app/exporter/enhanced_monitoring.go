@@ -0,0 +1,197 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/rds"
+)
+
+// osMetricsDescriptions are Prometheus descriptions of Enhanced Monitoring OS metrics
+type osMetricsDescriptions struct {
+	loadAverage              *prometheus.Desc
+	cpuUtilization           *prometheus.Desc
+	memory                   *prometheus.Desc
+	tasks                    *prometheus.Desc
+	networkReceiveThroughput *prometheus.Desc
+	networkSendThroughput    *prometheus.Desc
+	diskReadIOPS             *prometheus.Desc
+	diskWriteIOPS            *prometheus.Desc
+	diskReadThroughput       *prometheus.Desc
+	diskWriteThroughput      *prometheus.Desc
+	diskQueueLength          *prometheus.Desc
+	diskAwait                *prometheus.Desc
+	diskReadLatency          *prometheus.Desc
+	diskWriteLatency         *prometheus.Desc
+	fileSystemSize           *prometheus.Desc
+	fileSystemUsed           *prometheus.Desc
+	fileSystemMaxFiles       *prometheus.Desc
+	fileSystemUsedFiles      *prometheus.Desc
+	processMemory            *prometheus.Desc
+	processCPUUtilization    *prometheus.Desc
+	age                      *prometheus.Desc
+}
+
+func newOSMetricsDescriptions() osMetricsDescriptions {
+	newDesc := func(name string, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(name, help, append([]string{"aws_account_id", "aws_region", "dbidentifier"}, labels...), nil)
+	}
+
+	return osMetricsDescriptions{
+		loadAverage:              newDesc("rds_os_load_average", "Number of processes requesting CPU time over the period", "period"),
+		cpuUtilization:           newDesc("rds_os_cpu_utilization_percent", "Percentage of CPU in use by mode", "mode"),
+		memory:                   newDesc("rds_os_memory_bytes", "Amount of memory by type", "type"),
+		tasks:                    newDesc("rds_os_tasks_average", "Number of tasks by state", "state"),
+		networkReceiveThroughput: newDesc("rds_os_network_receive_throughput_bytes", "Number of bytes received per second by the network interface", "interface"),
+		networkSendThroughput:    newDesc("rds_os_network_transmit_throughput_bytes", "Number of bytes transmitted per second by the network interface", "interface"),
+		diskReadIOPS:             newDesc("rds_os_disk_read_iops_average", "Number of read operations per second of the device", "device"),
+		diskWriteIOPS:            newDesc("rds_os_disk_write_iops_average", "Number of write operations per second of the device", "device"),
+		diskReadThroughput:       newDesc("rds_os_disk_read_throughput_bytes", "Number of bytes read per second from the device", "device"),
+		diskWriteThroughput:      newDesc("rds_os_disk_write_throughput_bytes", "Number of bytes written per second to the device", "device"),
+		diskQueueLength:          newDesc("rds_os_disk_queue_length_average", "Number of requests waiting in the queue of the device", "device"),
+		diskAwait:                newDesc("rds_os_disk_await_seconds", "Average time to respond to requests of the device, including queue time", "device"),
+		diskReadLatency:          newDesc("rds_os_disk_read_latency_seconds", "Average time between the submission and the completion of read operations of the device", "device"),
+		diskWriteLatency:         newDesc("rds_os_disk_write_latency_seconds", "Average time between the submission and the completion of write operations of the device", "device"),
+		fileSystemSize:           newDesc("rds_os_filesystem_size_bytes", "Total size of the file system", "name", "mount_point"),
+		fileSystemUsed:           newDesc("rds_os_filesystem_used_bytes", "Space used by files in the file system", "name", "mount_point"),
+		fileSystemMaxFiles:       newDesc("rds_os_filesystem_max_files_average", "Maximum number of files of the file system", "name", "mount_point"),
+		fileSystemUsedFiles:      newDesc("rds_os_filesystem_used_files_average", "Number of files in the file system", "name", "mount_point"),
+		processMemory:            newDesc("rds_os_process_memory_bytes", "Resident memory of processes with this name", "process"),
+		processCPUUtilization:    newDesc("rds_os_process_cpu_utilization_percent", "Percentage of CPU used by processes with this name", "process"),
+		age:                      newDesc("rds_os_metrics_age_seconds", "Age of the latest Enhanced Monitoring document of the instance"),
+	}
+}
+
+func (d osMetricsDescriptions) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.loadAverage
+	ch <- d.cpuUtilization
+	ch <- d.memory
+	ch <- d.tasks
+	ch <- d.networkReceiveThroughput
+	ch <- d.networkSendThroughput
+	ch <- d.diskReadIOPS
+	ch <- d.diskWriteIOPS
+	ch <- d.diskReadThroughput
+	ch <- d.diskWriteThroughput
+	ch <- d.diskQueueLength
+	ch <- d.diskAwait
+	ch <- d.diskReadLatency
+	ch <- d.diskWriteLatency
+	ch <- d.fileSystemSize
+	ch <- d.fileSystemUsed
+	ch <- d.fileSystemMaxFiles
+	ch <- d.fileSystemUsedFiles
+	ch <- d.processMemory
+	ch <- d.processCPUUtilization
+	ch <- d.age
+}
+
+// getEnhancedMonitoringInstances returns instances with Enhanced Monitoring enabled, sorted by identifier
+func getEnhancedMonitoringInstances(instances map[string]rds.RdsInstanceMetrics) []enhancedmonitoring.Instance {
+	var emInstances []enhancedmonitoring.Instance
+
+	for dbIdentifier, instance := range instances {
+		if instance.MonitoringInterval > 0 && instance.DbiResourceID != "" {
+			emInstances = append(emInstances, enhancedmonitoring.Instance{DBIdentifier: dbIdentifier, DbiResourceID: instance.DbiResourceID})
+		}
+	}
+
+	slices.SortFunc(emInstances, func(a, b enhancedmonitoring.Instance) int {
+		return strings.Compare(a.DBIdentifier, b.DBIdentifier)
+	})
+
+	return emInstances
+}
+
+func (c *rdsCollector) getOSMetrics(ctx context.Context, instances []enhancedmonitoring.Instance) {
+	start := time.Now()
+
+	c.logger.Debug("fetch Enhanced Monitoring OS metrics")
+
+	fetcher := enhancedmonitoring.NewFetcher(ctx, c.cloudWatchLogsClient, c.logger)
+
+	// Metrics of successful instances are kept when some instances fail
+	osMetrics, err := fetcher.GetInstancesMetrics(instances)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("can't fetch Enhanced Monitoring OS metrics: %s", err))
+	}
+
+	c.freshness.markResult(collectorOSMetrics, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		metrics.OS = osMetrics
+	})
+
+	c.logger.Debug("Enhanced Monitoring OS metrics fetched", "instances", len(osMetrics.Instances))
+}
+
+// collectOSMetrics emits Enhanced Monitoring OS metrics of an instance
+func (c *rdsCollector) collectOSMetrics(ch chan<- prometheus.Metric, dbidentifier string, metrics enhancedmonitoring.OSMetrics, now time.Time) {
+	d := c.osMetrics
+
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append([]string{c.awsAccountID, c.awsRegion, dbidentifier}, labels...)...)
+	}
+
+	optionalGauge := func(desc *prometheus.Desc, value *float64, labels ...string) {
+		if value != nil {
+			gauge(desc, *value, labels...)
+		}
+	}
+
+	for period, value := range metrics.LoadAverage {
+		gauge(d.loadAverage, value, period)
+	}
+
+	for mode, value := range metrics.CPUUtilization {
+		gauge(d.cpuUtilization, value, mode)
+	}
+
+	for memoryType, value := range metrics.Memory {
+		gauge(d.memory, value, memoryType)
+	}
+
+	for state, value := range metrics.Tasks {
+		gauge(d.tasks, value, state)
+	}
+
+	for _, network := range metrics.Network {
+		gauge(d.networkReceiveThroughput, network.ReceiveThroughput, network.Interface)
+		gauge(d.networkSendThroughput, network.SendThroughput, network.Interface)
+	}
+
+	for _, disk := range metrics.DiskIO {
+		optionalGauge(d.diskReadIOPS, disk.ReadIOPS, disk.Device)
+		optionalGauge(d.diskWriteIOPS, disk.WriteIOPS, disk.Device)
+		optionalGauge(d.diskReadThroughput, disk.ReadThroughput, disk.Device)
+		optionalGauge(d.diskWriteThroughput, disk.WriteThroughput, disk.Device)
+		optionalGauge(d.diskQueueLength, disk.QueueLength, disk.Device)
+		optionalGauge(d.diskAwait, disk.Await, disk.Device)
+		optionalGauge(d.diskReadLatency, disk.ReadLatency, disk.Device)
+		optionalGauge(d.diskWriteLatency, disk.WriteLatency, disk.Device)
+	}
+
+	for _, fileSystem := range metrics.FileSystems {
+		gauge(d.fileSystemSize, fileSystem.Size, fileSystem.Name, fileSystem.MountPoint)
+		gauge(d.fileSystemUsed, fileSystem.Used, fileSystem.Name, fileSystem.MountPoint)
+		gauge(d.fileSystemMaxFiles, fileSystem.MaxFiles, fileSystem.Name, fileSystem.MountPoint)
+		gauge(d.fileSystemUsedFiles, fileSystem.UsedFiles, fileSystem.Name, fileSystem.MountPoint)
+	}
+
+	for name, process := range metrics.Processes {
+		gauge(d.processMemory, process.Memory, name)
+		gauge(d.processCPUUtilization, process.CPUUtilization, name)
+	}
+
+	if !metrics.Timestamp.IsZero() {
+		gauge(d.age, now.Sub(metrics.Timestamp).Seconds())
+	}
+}
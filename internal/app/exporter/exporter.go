@@ -3,24 +3,31 @@ package exporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/cloudwatch"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/ec2"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/performanceinsights"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/rds"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/servicequotas"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/trace"
+	converter "github.com/qonto/prometheus-rds-exporter/internal/app/unit"
+	"github.com/qonto/prometheus-rds-exporter/internal/infra/awsapi"
 	"github.com/qonto/prometheus-rds-exporter/internal/infra/build"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -28,30 +35,87 @@ const (
 	exporterDownStatusCode float64 = 0
 )
 
+// defaultQuotasAPICalls estimates AWS API calls of the first fetch of AWS RDS quotas, one page of each quotas list
+const defaultQuotasAPICalls = 2
+
 var tracer = otel.Tracer("github/qonto/prometheus-rds-exporter/internal/app/exporter")
 
+// APIBudget reports the remaining AWS API calls budget of AWS services
+type APIBudget interface {
+	// Available returns true if the budget of the AWS service allows calls without waiting
+	Available(awsAccountID string, awsRegion string, service string, calls int) bool
+}
+
 type Configuration struct {
-	CollectInstanceMetrics    bool
-	CollectInstanceTags       bool
-	CollectInstanceTypes      bool
-	CollectLogsSize           bool
-	CollectServerlessLogsSize bool
-	CollectMaintenances       bool
-	CollectClusterMetrics     bool
-	CollectQuotas             bool
-	CollectUsages             bool
-	CollectEngineSupport      bool
-	TagSelections             map[string][]string
+	CollectInstanceMetrics     bool
+	CollectInstanceTags        bool
+	CollectInstanceTypes       bool
+	CollectLogsSize            bool
+	CollectServerlessLogsSize  bool
+	CollectMaintenances        bool
+	CollectClusterMetrics      bool
+	CollectQuotas              bool
+	CollectUsages              bool
+	CollectEngineSupport       bool
+	CollectProxies             bool
+	CollectPerformanceInsights bool
+	CollectOSMetrics           bool
+	TagSelections              map[string][]string
+
+	// PerformanceInsightsTopN is the number of top wait events and SQL statements collected for each instance.
+	// When zero, performanceinsights.DefaultTopN are collected. It is capped to performanceinsights.MaxTopN.
+	PerformanceInsightsTopN int
+
+	// QuotaCodes are the AWS RDS quota codes exported by rds_quota_value.
+	// When empty, all AWS RDS quotas are exported.
+	QuotaCodes []string
+
+	// RefreshInterval defines how often AWS APIs are queried in the background.
+	// When zero, AWS APIs are queried on every scrape.
+	RefreshInterval time.Duration
+
+	// Minimum duration between two fetches of each data source.
+	// When zero, the data source is fetched on every refresh.
+	RDSRefreshInterval                 time.Duration
+	CloudWatchRefreshInterval          time.Duration
+	UsageRefreshInterval               time.Duration
+	EC2RefreshInterval                 time.Duration
+	ServiceQuotasRefreshInterval       time.Duration
+	EngineSupportRefreshInterval       time.Duration
+	PerformanceInsightsRefreshInterval time.Duration
+	OSMetricsRefreshInterval           time.Duration
+
+	// ScrapeTimeout cancels outstanding AWS API calls of a collection after this duration.
+	// Collectors that did not finish in time are marked as failed. When zero, collections have no deadline.
+	ScrapeTimeout time.Duration
+
+	// APIBudget skips low priority collectors (logs size, engine support and quotas) when the AWS API budget is exhausted.
+	// When nil, collectors are never skipped.
+	APIBudget APIBudget
+
+	// CloudWatchTimestamps attaches the CloudWatch datapoint timestamp to exported CloudWatch metrics.
+	// When false, CloudWatch metrics are exported without timestamp, as if they were current.
+	CloudWatchTimestamps bool
+
+	// CloudWatchMetrics are CloudWatch metrics collected for each instance.
+	// When empty, cloudwatch.DefaultMetricDefinitions are collected.
+	CloudWatchMetrics []cloudwatch.MetricDefinition
+
+	// CloudWatchClusterMetrics are CloudWatch metrics collected for each Aurora cluster when cluster and instance metrics are collected.
+	// When empty, cloudwatch.DefaultClusterMetricDefinitions are collected.
+	CloudWatchClusterMetrics []cloudwatch.MetricDefinition
 }
 
 type counters struct {
-	CloudwatchAPICalls    float64
-	EC2APIcalls           float64
-	Errors                float64
-	RDSAPIcalls           float64
-	ServiceQuotasAPICalls float64
-	UsageAPIcalls         float64
-	TagAPICalls           float64
+	CloudwatchIncomplete float64
+	Errors               float64
+}
+
+func (c counters) add(other counters) counters {
+	return counters{
+		CloudwatchIncomplete: c.CloudwatchIncomplete + other.CloudwatchIncomplete,
+		Errors:               c.Errors + other.Errors,
+	}
 }
 
 type metrics struct {
@@ -59,30 +123,54 @@ type metrics struct {
 	RDS                 rds.Metrics
 	EC2                 ec2.Metrics
 	CloudwatchInstances cloudwatch.CloudWatchMetrics
+	CloudwatchClusters  cloudwatch.CloudWatchMetrics
+	CloudwatchProxies   cloudwatch.CloudWatchMetrics
 	CloudWatchUsage     cloudwatch.UsageMetrics
+	EngineSupport       map[string]rds.EngineSupportMetrics
+	Proxies             map[string]rds.ProxyMetrics
+	PerformanceInsights performanceinsights.Metrics
+	OS                  enhancedmonitoring.Metrics
+}
+
+// snapshot is the result of the last collection of AWS APIs
+type snapshot struct {
+	ready       bool
+	err         error
+	counters    counters
+	metrics     metrics
+	lastSuccess map[string]time.Time
+	lastResult  map[string]collectorResult
+	skipped     map[string]float64
+}
+
+// healthy returns true if the last collection of AWS APIs succeeded
+func (s snapshot) healthy() bool {
+	return s.ready && s.err == nil
 }
 
 type rdsCollector struct {
-	ctx           context.Context
-	wg            sync.WaitGroup
 	logger        slog.Logger
+	stateMutex    sync.Mutex // protects counters, metrics, ec2InstanceTypes and quotasAPICalls written by concurrent fetchers
 	counters      counters
 	metrics       metrics
+	coalescer     coalescer    // ensures only one collection of AWS APIs runs at a time
+	snapshotMutex sync.RWMutex // protects snapshot
+	snapshot      snapshot
+	freshness     *freshness
 	awsAccountID  string
 	awsRegion     string
 	configuration Configuration
 
-	rdsClient            rdsClient
-	EC2Client            EC2Client
-	servicequotasClient  servicequotasClient
-	cloudWatchClient     cloudWatchClient
-	tagClient            resourcegroupstaggingapi.GetResourcesAPIClient
-	engineSupportService *rds.EngineSupportService
+	rdsClient                 rdsClient
+	EC2Client                 EC2Client
+	servicequotasClient       servicequotasClient
+	cloudWatchClient          cloudWatchClient
+	tagClient                 resourcegroupstaggingapi.GetResourcesAPIClient
+	performanceInsightsClient performanceinsights.Client
+	cloudWatchLogsClient      enhancedmonitoring.Client
+	engineSupportService      *rds.EngineSupportService
 
 	errors                           *prometheus.Desc
-	DBLoad                           *prometheus.Desc
-	dBLoadCPU                        *prometheus.Desc
-	dBLoadNonCPU                     *prometheus.Desc
 	allocatedStorage                 *prometheus.Desc
 	allocatedDiskIOPS                *prometheus.Desc
 	allocatedDiskThroughput          *prometheus.Desc
@@ -90,11 +178,17 @@ type rdsCollector struct {
 	clusterInformation               *prometheus.Desc
 	clusterServerLessMaxACU          *prometheus.Desc
 	clusterServerLessMinACU          *prometheus.Desc
+	proxyInformation                 *prometheus.Desc
+	proxyTargetHealth                *prometheus.Desc
+	performanceInsightsWaitEventLoad *prometheus.Desc
+	performanceInsightsSQLLoad       *prometheus.Desc
 	instanceBaselineIops             *prometheus.Desc
 	instanceMaximumIops              *prometheus.Desc
 	instanceBaselineThroughput       *prometheus.Desc
 	instanceMaximumThroughput        *prometheus.Desc
 	instanceBaselineNetworkBandwidth *prometheus.Desc
+	instanceBurstablePerformance     *prometheus.Desc
+	instanceBurstableEBS             *prometheus.Desc
 	instanceMemory                   *prometheus.Desc
 	instanceVCPU                     *prometheus.Desc
 	instanceTags                     *prometheus.Desc
@@ -104,53 +198,128 @@ type rdsCollector struct {
 	status                           *prometheus.Desc
 	storageThroughput                *prometheus.Desc
 	maxNetworkThroughput             *prometheus.Desc
-	networkReceiveThroughput         *prometheus.Desc
-	networkTransmitThroughput        *prometheus.Desc
 	up                               *prometheus.Desc
-	cpuUtilisation                   *prometheus.Desc
-	freeStorageSpace                 *prometheus.Desc
-	databaseConnections              *prometheus.Desc
-	freeableMemory                   *prometheus.Desc
-	swapUsage                        *prometheus.Desc
-	writeIOPS                        *prometheus.Desc
-	readIOPS                         *prometheus.Desc
-	replicaLag                       *prometheus.Desc
-	replicationSlotDiskUsage         *prometheus.Desc
-	maximumUsedTransactionIDs        *prometheus.Desc
-	apiCall                          *prometheus.Desc
-	readThroughput                   *prometheus.Desc
-	writeThroughput                  *prometheus.Desc
-	storageNetworkReceiveThroughput  *prometheus.Desc
-	storageNetworkTransmitThroughput *prometheus.Desc
 	backupRetentionPeriod            *prometheus.Desc
 	quotaDBInstances                 *prometheus.Desc
 	quotaTotalStorage                *prometheus.Desc
 	quotaMaxDBInstanceSnapshots      *prometheus.Desc
+	quotaResources                   *prometheus.Desc
+	quotaUtilization                 *prometheus.Desc
+	quotaValue                       *prometheus.Desc
 	usageAllocatedStorage            *prometheus.Desc
 	usageDBInstances                 *prometheus.Desc
 	usageManualSnapshots             *prometheus.Desc
-	serverlessDatabaseCapacity       *prometheus.Desc
+	usageResources                   *prometheus.Desc
 	exporterBuildInformation         *prometheus.Desc
-	transactionLogsDiskUsage         *prometheus.Desc
 	certificateValidTill             *prometheus.Desc
 	age                              *prometheus.Desc
 	standardSupportRemainingDays     *prometheus.Desc
 	extendedSupportRemainingDays     *prometheus.Desc
+	collectorLastSuccess             *prometheus.Desc
+	collectorSuccess                 *prometheus.Desc
+	collectorDuration                *prometheus.Desc
+	collectorSkipped                 *prometheus.Desc
+	targetUp                         *prometheus.Desc
+	cloudwatchIncompleteResults      *prometheus.Desc
+	coalescedScrapes                 *prometheus.Desc
+	osMetrics                        osMetricsDescriptions
+
+	// CloudWatch metrics collected for each instance, each Aurora cluster, each proxy and each proxy target
+	cloudwatchDefinitions             []cloudwatch.MetricDefinition
+	cloudwatchMetrics                 map[string]cloudwatchMetric // Prometheus metric name => description
+	cloudwatchClusterDefinitions      []cloudwatch.MetricDefinition
+	cloudwatchClusterMetrics          map[string]cloudwatchMetric // Prometheus metric name => description
+	cloudwatchProxyMetrics            map[string]cloudwatchMetric // Prometheus metric name => description
+	cloudwatchProxyTargetMetrics      map[string]cloudwatchMetric // Prometheus metric name => description
+	cloudwatchDatapointAge            *prometheus.Desc
+	cloudwatchClusterDatapointAge     *prometheus.Desc
+	cloudwatchProxyDatapointAge       *prometheus.Desc
+	cloudwatchProxyTargetDatapointAge *prometheus.Desc
+
+	// instance types of the last successful EC2 fetch
+	ec2InstanceTypes []string
+
+	// AWS API calls of the last quotas fetch, estimates the budget of the next fetch
+	quotasAPICalls int
+}
+
+// cloudwatchMetric is the Prometheus description of a CloudWatch metric definition
+type cloudwatchMetric struct {
+	desc           *prometheus.Desc
+	statisticLabel bool // CloudWatch statistic is exported as statistic label
 }
 
-func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsAccountID string, awsRegion string, rdsClient rdsClient, ec2Client EC2Client, cloudWatchClient cloudWatchClient, servicequotasClient servicequotasClient, tagClient resourcegroupstaggingapi.GetResourcesAPIClient) *rdsCollector {
+// newCloudWatchMetricsDescriptions returns Prometheus descriptions of CloudWatch metrics by Prometheus metric name
+// identifierLabels are the labels identifying the instance, cluster, proxy or proxy target
+func newCloudWatchMetricsDescriptions(definitions []cloudwatch.MetricDefinition, identifierLabels ...string) map[string]cloudwatchMetric {
+	descriptions := make(map[string]cloudwatchMetric, len(definitions))
+
+	for _, definition := range definitions {
+		labels := append([]string{"aws_account_id", "aws_region"}, identifierLabels...)
+		if definition.HasStatisticLabel() {
+			labels = append(labels, "statistic")
+		}
+
+		descriptions[definition.Name] = cloudwatchMetric{
+			desc:           prometheus.NewDesc(definition.Name, definition.Help, labels, nil),
+			statisticLabel: definition.HasStatisticLabel(),
+		}
+	}
+
+	return descriptions
+}
+
+func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsAccountID string, awsRegion string, rdsClient rdsClient, ec2Client EC2Client, cloudWatchClient cloudWatchClient, servicequotasClient servicequotasClient, tagClient resourcegroupstaggingapi.GetResourcesAPIClient, performanceInsightsClient performanceinsights.Client, cloudWatchLogsClient enhancedmonitoring.Client) *rdsCollector {
+	cloudwatchDefinitions := collectorConfiguration.CloudWatchMetrics
+	if len(cloudwatchDefinitions) == 0 {
+		cloudwatchDefinitions = cloudwatch.DefaultMetricDefinitions()
+	}
+
+	cloudwatchClusterDefinitions := collectorConfiguration.CloudWatchClusterMetrics
+	if len(cloudwatchClusterDefinitions) == 0 {
+		cloudwatchClusterDefinitions = cloudwatch.DefaultClusterMetricDefinitions()
+	}
+
 	return &rdsCollector{
-		logger:              logger,
-		awsAccountID:        awsAccountID,
-		awsRegion:           awsRegion,
-		rdsClient:           rdsClient,
-		servicequotasClient: servicequotasClient,
-		EC2Client:           ec2Client,
-		cloudWatchClient:    cloudWatchClient,
-		tagClient:           tagClient,
-
-		configuration:        collectorConfiguration,
-		engineSupportService: rds.NewEngineSupportService(rdsClient, &logger),
+		logger:                    logger,
+		awsAccountID:              awsAccountID,
+		awsRegion:                 awsRegion,
+		rdsClient:                 rdsClient,
+		servicequotasClient:       servicequotasClient,
+		EC2Client:                 ec2Client,
+		cloudWatchClient:          cloudWatchClient,
+		tagClient:                 tagClient,
+		performanceInsightsClient: performanceInsightsClient,
+		cloudWatchLogsClient:      cloudWatchLogsClient,
+
+		configuration:                collectorConfiguration,
+		engineSupportService:         rds.NewEngineSupportService(rdsClient, &logger),
+		freshness:                    newFreshness(),
+		coalescer:                    coalescer{timeout: collectorConfiguration.ScrapeTimeout},
+		cloudwatchDefinitions:        cloudwatchDefinitions,
+		cloudwatchMetrics:            newCloudWatchMetricsDescriptions(cloudwatchDefinitions, "dbidentifier"),
+		cloudwatchClusterDefinitions: cloudwatchClusterDefinitions,
+		cloudwatchClusterMetrics:     newCloudWatchMetricsDescriptions(cloudwatchClusterDefinitions, "cluster_identifier"),
+		cloudwatchProxyMetrics:       newCloudWatchMetricsDescriptions(cloudwatch.DefaultProxyMetricDefinitions(), "proxy"),
+		cloudwatchProxyTargetMetrics: newCloudWatchMetricsDescriptions(cloudwatch.DefaultProxyTargetMetricDefinitions(), "proxy", "target_group", "dbidentifier"),
+		cloudwatchDatapointAge: prometheus.NewDesc("rds_cloudwatch_datapoint_age_seconds",
+			"Age of the latest CloudWatch datapoint of the metric, from the start of its CloudWatch period",
+			[]string{"aws_account_id", "aws_region", "dbidentifier", "metric"}, nil,
+		),
+		cloudwatchClusterDatapointAge: prometheus.NewDesc("rds_cloudwatch_cluster_datapoint_age_seconds",
+			"Age of the latest CloudWatch datapoint of the cluster metric, from the start of its CloudWatch period",
+			[]string{"aws_account_id", "aws_region", "cluster_identifier", "metric"}, nil,
+		),
+		cloudwatchProxyDatapointAge: prometheus.NewDesc("rds_cloudwatch_proxy_datapoint_age_seconds",
+			"Age of the latest CloudWatch datapoint of the proxy metric, from the start of its CloudWatch period",
+			[]string{"aws_account_id", "aws_region", "proxy", "metric"}, nil,
+		),
+		cloudwatchProxyTargetDatapointAge: prometheus.NewDesc("rds_cloudwatch_proxy_target_datapoint_age_seconds",
+			"Age of the latest CloudWatch datapoint of the proxy target metric, from the start of its CloudWatch period",
+			[]string{"aws_account_id", "aws_region", "proxy", "target_group", "dbidentifier", "metric"}, nil,
+		),
+
+		osMetrics: newOSMetricsDescriptions(),
 
 		exporterBuildInformation: prometheus.NewDesc("rds_exporter_build_info",
 			"A metric with constant '1' value labeled by version from which exporter was built",
@@ -160,6 +329,34 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 			"Total number of errors encountered by the exporter",
 			[]string{}, nil,
 		),
+		collectorLastSuccess: prometheus.NewDesc("rds_exporter_collector_last_success_timestamp_seconds",
+			"Timestamp of the last successful fetch of the collector",
+			[]string{"aws_account_id", "aws_region", "collector"}, nil,
+		),
+		collectorSuccess: prometheus.NewDesc("rds_exporter_collector_success",
+			"Whether the last fetch of the collector succeeded",
+			[]string{"aws_account_id", "aws_region", "collector"}, nil,
+		),
+		collectorDuration: prometheus.NewDesc("rds_exporter_collector_duration_seconds",
+			"Duration of the last fetch of the collector",
+			[]string{"aws_account_id", "aws_region", "collector"}, nil,
+		),
+		collectorSkipped: prometheus.NewDesc("rds_exporter_collector_skipped_total",
+			"Total number of refreshes where the collector was skipped because the AWS API budget was exhausted",
+			[]string{"aws_account_id", "aws_region", "collector"}, nil,
+		),
+		targetUp: prometheus.NewDesc("rds_exporter_target_up",
+			"Was the last refresh of the AWS account and region successful",
+			[]string{"aws_account_id", "aws_region"}, nil,
+		),
+		cloudwatchIncompleteResults: prometheus.NewDesc("rds_exporter_cloudwatch_incomplete_results_total",
+			"Total number of CloudWatch queries without complete data (partial data, internal error or failed request)",
+			[]string{"aws_account_id", "aws_region"}, nil,
+		),
+		coalescedScrapes: prometheus.NewDesc("rds_exporter_coalesced_scrapes_total",
+			"Total number of scrapes that waited for an in-flight collection of AWS APIs instead of starting a new one",
+			[]string{}, nil,
+		),
 		allocatedStorage: prometheus.NewDesc("rds_allocated_storage_bytes",
 			"Allocated storage",
 			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
@@ -188,6 +385,22 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 			"Minimum number of ACU",
 			[]string{"aws_account_id", "aws_region", "cluster_identifier"}, nil,
 		),
+		proxyInformation: prometheus.NewDesc("rds_proxy_info",
+			"RDS proxy information",
+			[]string{"aws_account_id", "aws_region", "proxy", "engine_family", "status", "require_tls", "arn"}, nil,
+		),
+		proxyTargetHealth: prometheus.NewDesc("rds_proxy_target_health",
+			"Proxy target health state (1 available | 0 registering) (-1 unavailable | -2 unknown)",
+			[]string{"aws_account_id", "aws_region", "proxy", "target_group", "dbidentifier", "type", "role", "reason"}, nil,
+		),
+		performanceInsightsWaitEventLoad: prometheus.NewDesc("rds_performance_insights_wait_event_load_average",
+			"Average active sessions of the top wait events of the instance, from Performance Insights db.load.avg",
+			[]string{"aws_account_id", "aws_region", "dbidentifier", "wait_event_type", "wait_event"}, nil,
+		),
+		performanceInsightsSQLLoad: prometheus.NewDesc("rds_performance_insights_sql_load_average",
+			"Average active sessions of the top tokenized SQL statements of the instance, from Performance Insights db.load.avg",
+			[]string{"aws_account_id", "aws_region", "dbidentifier", "sql_id", "statement"}, nil,
+		),
 		age: prometheus.NewDesc("rds_instance_age_seconds",
 			"Time since instance creation",
 			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
@@ -208,30 +421,6 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 			"Maximum network throughput of underlying EC2 instance class",
 			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
 		),
-		networkReceiveThroughput: prometheus.NewDesc("rds_network_receive_throughput_bytes",
-			"Average number of bytes received per second from the network",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		networkTransmitThroughput: prometheus.NewDesc("rds_network_transmit_throughput_bytes",
-			"Average number of bytes transmitted per second to the network",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		readThroughput: prometheus.NewDesc("rds_read_throughput_bytes",
-			"Average number of bytes read from disk per second",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		writeThroughput: prometheus.NewDesc("rds_write_throughput_bytes",
-			"Average number of bytes written to disk per second",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		storageNetworkReceiveThroughput: prometheus.NewDesc("rds_storage_network_receive_throughput_bytes",
-			"Average number of bytes received per second from the Aurora storage subsystem (Aurora only)",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		storageNetworkTransmitThroughput: prometheus.NewDesc("rds_storage_network_transmit_throughput_bytes",
-			"Average number of bytes transmitted per second to the Aurora storage subsystem (Aurora only)",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
 		status: prometheus.NewDesc("rds_instance_status",
 			"Instance status (0 stopped or can't scrape) (1 ok | 2 backup | 3 startup | 4 modify | 5 monitoring config | 1X storage | 20 renaming) (-1 unknown | -2 stopping | -3 creating | -4 deleting | -5 rebooting | -6 failed | -7 full storage | -8 upgrading | -9 maintenance | -10 restore error)",
 			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
@@ -252,10 +441,6 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 			"AWS tags attached to the instance",
 			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
 		),
-		cpuUtilisation: prometheus.NewDesc("rds_cpu_usage_percent_average",
-			"Instance CPU used",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
 		instanceMaximumThroughput: prometheus.NewDesc("rds_instance_max_throughput_bytes",
 			"Maximum throughput of underlying EC2 instance class",
 			[]string{"aws_account_id", "aws_region", "instance_class"}, nil,
@@ -276,70 +461,22 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 			"Baseline network bandwidth of underlying EC2 instance class",
 			[]string{"aws_account_id", "aws_region", "instance_class"}, nil,
 		),
-		freeStorageSpace: prometheus.NewDesc("rds_free_storage_bytes",
-			"Free storage on the instance",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
+		instanceBurstablePerformance: prometheus.NewDesc("rds_instance_burstable_performance",
+			"1 if the instance class has burstable CPU performance using CPU credits",
+			[]string{"aws_account_id", "aws_region", "instance_class"}, nil,
 		),
-		databaseConnections: prometheus.NewDesc("rds_database_connections_average",
-			"The number of client network connections to the database instance",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
+		instanceBurstableEBS: prometheus.NewDesc("rds_instance_burstable_ebs",
+			"1 if the instance class can burst over its EBS baseline bandwidth using EBS credits",
+			[]string{"aws_account_id", "aws_region", "instance_class"}, nil,
 		),
 		up: prometheus.NewDesc("up",
 			"Was the last scrape of RDS successful",
 			nil, nil,
 		),
-		swapUsage: prometheus.NewDesc("rds_swap_usage_bytes",
-			"Amount of swap space used on the DB instance. This metric is not available for SQL Server",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		writeIOPS: prometheus.NewDesc("rds_write_iops_average",
-			"Average number of disk write I/O operations per second",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		readIOPS: prometheus.NewDesc("rds_read_iops_average",
-			"Average number of disk read I/O operations per second",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		replicaLag: prometheus.NewDesc("rds_replica_lag_seconds",
-			"For read replica configurations, the amount of time a read replica DB instance lags behind the source DB instance. Applies to MariaDB, Microsoft SQL Server, MySQL, Oracle, and PostgreSQL read replicas",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		replicationSlotDiskUsage: prometheus.NewDesc("rds_replication_slot_disk_usage_bytes",
-			"Disk space used by replication slot files. Applies to PostgreSQL",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		maximumUsedTransactionIDs: prometheus.NewDesc("rds_maximum_used_transaction_ids_average",
-			"Maximum transaction IDs that have been used. Applies to only PostgreSQL",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		freeableMemory: prometheus.NewDesc("rds_freeable_memory_bytes",
-			"Amount of available random access memory. For MariaDB, MySQL, Oracle, and PostgreSQL DB instances, this metric reports the value of the MemAvailable field of /proc/meminfo",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		apiCall: prometheus.NewDesc("rds_api_call_total",
-			"Number of call to AWS API",
-			[]string{"aws_account_id", "aws_region", "api"}, nil,
-		),
 		backupRetentionPeriod: prometheus.NewDesc("rds_backup_retention_period_seconds",
 			"Automatic DB snapshots retention period",
 			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
 		),
-		DBLoad: prometheus.NewDesc("rds_dbload_average",
-			"Number of active sessions for the DB engine",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		dBLoadCPU: prometheus.NewDesc("rds_dbload_cpu_average",
-			"Number of active sessions where the wait event type is CPU",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		dBLoadNonCPU: prometheus.NewDesc("rds_dbload_noncpu_average",
-			"Number of active sessions where the wait event type is not CPU",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
-		transactionLogsDiskUsage: prometheus.NewDesc("rds_transaction_logs_disk_usage_bytes",
-			"Disk space used by transaction logs (only on PostgreSQL)",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
-		),
 		certificateValidTill: prometheus.NewDesc("rds_certificate_expiry_timestamp_seconds",
 			"Timestamp of the expiration of the Instance certificate",
 			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
@@ -356,6 +493,18 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 			"Maximum number of manual DB instance snapshots",
 			[]string{"aws_account_id", "aws_region"}, nil,
 		),
+		quotaResources: prometheus.NewDesc("rds_quota_resource_average",
+			"Maximum number of RDS resources allowed in the AWS account by AWS/Usage resource",
+			[]string{"aws_account_id", "aws_region", "resource"}, nil,
+		),
+		quotaUtilization: prometheus.NewDesc("rds_quota_utilization_ratio",
+			"Ratio of the RDS service quota used by AWS/Usage resource",
+			[]string{"aws_account_id", "aws_region", "resource"}, nil,
+		),
+		quotaValue: prometheus.NewDesc("rds_quota_value",
+			"Value of the AWS RDS service quota, applied to the AWS account or AWS default",
+			[]string{"aws_account_id", "aws_region", "quota_code", "quota_name", "adjustable", "type"}, nil,
+		),
 		usageAllocatedStorage: prometheus.NewDesc("rds_usage_allocated_storage_bytes",
 			"Total storage used by AWS RDS instances",
 			[]string{"aws_account_id", "aws_region"}, nil,
@@ -368,9 +517,9 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 			"Manual snapshots count",
 			[]string{"aws_account_id", "aws_region"}, nil,
 		),
-		serverlessDatabaseCapacity: prometheus.NewDesc("rds_serverless_instance_acu_average",
-			"Current ACU of the Aurora Serverless instance",
-			[]string{"aws_account_id", "aws_region", "dbidentifier"}, nil,
+		usageResources: prometheus.NewDesc("rds_usage_resource_average",
+			"Number of RDS resources in the AWS account by AWS/Usage resource",
+			[]string{"aws_account_id", "aws_region", "resource"}, nil,
 		),
 		standardSupportRemainingDays: prometheus.NewDesc("rds_standard_support_engine_remaining_days",
 			"Days remaining until standard support ends for the database engine version.",
@@ -384,197 +533,725 @@ func NewCollector(logger slog.Logger, collectorConfiguration Configuration, awsA
 }
 
 func (c *rdsCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.DBLoad
+	for _, metric := range c.cloudwatchMetrics {
+		ch <- metric.desc
+	}
+
+	for _, metric := range c.cloudwatchClusterMetrics {
+		ch <- metric.desc
+	}
+
+	for _, metric := range c.cloudwatchProxyMetrics {
+		ch <- metric.desc
+	}
+
+	for _, metric := range c.cloudwatchProxyTargetMetrics {
+		ch <- metric.desc
+	}
+
+	ch <- c.cloudwatchDatapointAge
+	ch <- c.cloudwatchClusterDatapointAge
+	ch <- c.cloudwatchProxyDatapointAge
+	ch <- c.cloudwatchProxyTargetDatapointAge
+
+	c.osMetrics.describe(ch)
+
 	ch <- c.age
 	ch <- c.allocatedStorage
 	ch <- c.allocatedDiskIOPS
 	ch <- c.allocatedDiskThroughput
-	ch <- c.apiCall
-	ch <- c.apiCall
 	ch <- c.backupRetentionPeriod
 	ch <- c.certificateValidTill
-	ch <- c.cpuUtilisation
-	ch <- c.dBLoadCPU
-	ch <- c.dBLoadNonCPU
-	ch <- c.databaseConnections
 	ch <- c.errors
 	ch <- c.exporterBuildInformation
-	ch <- c.freeStorageSpace
-	ch <- c.freeableMemory
 	ch <- c.information
 	ch <- c.clusterInformation
+	ch <- c.clusterServerLessMaxACU
+	ch <- c.clusterServerLessMinACU
+	ch <- c.proxyInformation
+	ch <- c.proxyTargetHealth
+	ch <- c.performanceInsightsWaitEventLoad
+	ch <- c.performanceInsightsSQLLoad
+	ch <- c.collectorLastSuccess
+	ch <- c.collectorSuccess
+	ch <- c.collectorDuration
+	ch <- c.collectorSkipped
+	ch <- c.targetUp
+	ch <- c.cloudwatchIncompleteResults
+	ch <- c.coalescedScrapes
 	ch <- c.instanceBaselineIops
 	ch <- c.instanceMaximumIops
 	ch <- c.instanceBaselineThroughput
 	ch <- c.instanceMaximumThroughput
 	ch <- c.instanceBaselineNetworkBandwidth
+	ch <- c.instanceBurstablePerformance
+	ch <- c.instanceBurstableEBS
 	ch <- c.instanceMemory
 	ch <- c.instanceVCPU
 	ch <- c.logFilesSize
 	ch <- c.maxAllocatedStorage
 	ch <- c.maxIops
-	ch <- c.maximumUsedTransactionIDs
 	ch <- c.maxNetworkThroughput
-	ch <- c.networkReceiveThroughput
-	ch <- c.networkTransmitThroughput
 	ch <- c.quotaDBInstances
 	ch <- c.quotaMaxDBInstanceSnapshots
+	ch <- c.quotaResources
 	ch <- c.quotaTotalStorage
-	ch <- c.readIOPS
-	ch <- c.readThroughput
-	ch <- c.replicaLag
-	ch <- c.replicationSlotDiskUsage
+	ch <- c.quotaUtilization
+	ch <- c.quotaValue
 	ch <- c.status
 	ch <- c.storageThroughput
-	ch <- c.storageNetworkReceiveThroughput
-	ch <- c.storageNetworkTransmitThroughput
-	ch <- c.swapUsage
-	ch <- c.transactionLogsDiskUsage
 	ch <- c.up
 	ch <- c.usageAllocatedStorage
 	ch <- c.usageDBInstances
 	ch <- c.usageManualSnapshots
-	ch <- c.serverlessDatabaseCapacity
+	ch <- c.usageResources
 	ch <- c.standardSupportRemainingDays
 	ch <- c.extendedSupportRemainingDays
-	ch <- c.writeIOPS
-	ch <- c.writeThroughput
 }
 
-// getMetrics collects and return all RDS metrics
-func (c *rdsCollector) fetchMetrics() error {
+// fetchMetrics collects all RDS metrics from AWS APIs
+// Data sources are only fetched when their refresh interval is elapsed, otherwise previous values are kept
+func (c *rdsCollector) fetchMetrics(ctx context.Context) error {
 	c.logger.Debug("received query")
 
+	now := time.Now()
+
+	var wg sync.WaitGroup
+
 	// Fetch serviceQuotas metrics
-	if c.configuration.CollectQuotas {
-		go c.getQuotasMetrics(c.servicequotasClient)
-		c.wg.Add(1)
+	if c.configuration.CollectQuotas && c.freshness.isStale(collectorServiceQuotas, c.configuration.ServiceQuotasRefreshInterval, now) && c.isBudgetAvailable(collectorServiceQuotas, awsapi.ServiceQuotasService, c.estimateQuotasAPICalls()) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.getQuotasMetrics(ctx, c.servicequotasClient)
+		}()
 	}
 
 	// Fetch usages metrics
-	if c.configuration.CollectUsages {
-		go c.getUsagesMetrics(c.cloudWatchClient)
-		c.wg.Add(1)
+	if c.configuration.CollectUsages && c.freshness.isStale(collectorUsage, c.configuration.UsageRefreshInterval, now) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.getUsagesMetrics(ctx, c.cloudWatchClient)
+		}()
 	}
 
 	// Fetch RDS instances metrics
+	if c.freshness.isStale(collectorRDS, c.configuration.RDSRefreshInterval, now) {
+		err := c.getRDSMetrics(ctx)
+		if err != nil {
+			// Wait for already started go routines before returning
+			wg.Wait()
+
+			return err
+		}
+	}
+
+	// Fetch RDS proxies
+	if c.configuration.CollectProxies && c.freshness.isStale(collectorProxies, c.configuration.RDSRefreshInterval, now) {
+		c.getProxiesMetrics(ctx)
+	}
+
+	var (
+		rdsMetrics       rds.Metrics
+		ec2Metrics       ec2.Metrics
+		ec2InstanceTypes []string
+		proxies          map[string]rds.ProxyMetrics
+	)
+
+	c.update(func(_ *counters, metrics *metrics) {
+		rdsMetrics = metrics.RDS
+		ec2Metrics = metrics.EC2
+		ec2InstanceTypes = c.ec2InstanceTypes
+		proxies = metrics.Proxies
+	})
+
+	// Compute uniq instance types
+	_, instanceTypes := getUniqTypeAndIdentifiers(rdsMetrics.Instances)
+
+	// Fetch EC2 Metrics for instance types. New instance types are fetched immediately
+	if c.configuration.CollectInstanceTypes && len(instanceTypes) > 0 {
+		if c.freshness.isStale(collectorEC2, c.configuration.EC2RefreshInterval, now) || !slices.Equal(instanceTypes, ec2InstanceTypes) {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				c.getEC2Metrics(ctx, c.EC2Client, instanceTypes)
+			}()
+		}
+	}
+
+	// Fetch Cloudwatch metrics for instances
+	if c.configuration.CollectInstanceMetrics && c.freshness.isStale(collectorCloudWatch, c.configuration.CloudWatchRefreshInterval, now) {
+		instances := getCloudWatchInstances(rdsMetrics.Instances, ec2Metrics)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.getCloudwatchMetrics(ctx, c.cloudWatchClient, instances)
+		}()
+	}
+
+	// Fetch Cloudwatch metrics for Aurora clusters
+	if c.configuration.CollectInstanceMetrics && c.configuration.CollectClusterMetrics && c.freshness.isStale(collectorCloudWatchClusters, c.configuration.CloudWatchRefreshInterval, now) {
+		clusters := getAuroraClusters(rdsMetrics.Clusters)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.getCloudwatchClusterMetrics(ctx, c.cloudWatchClient, clusters)
+		}()
+	}
+
+	// Fetch Cloudwatch metrics for proxies and their instance targets
+	if c.configuration.CollectInstanceMetrics && c.configuration.CollectProxies && c.freshness.isStale(collectorCloudWatchProxies, c.configuration.CloudWatchRefreshInterval, now) {
+		proxyResources, targetResources := getCloudWatchProxies(proxies)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.getCloudwatchProxyMetrics(ctx, c.cloudWatchClient, proxyResources, targetResources)
+		}()
+	}
+
+	// Fetch Performance Insights top wait events and SQL statements of instances
+	if c.configuration.CollectPerformanceInsights && c.freshness.isStale(collectorPerformanceInsights, c.configuration.PerformanceInsightsRefreshInterval, now) {
+		instances := getPerformanceInsightsInstances(rdsMetrics.Instances)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.getPerformanceInsightsMetrics(ctx, instances)
+		}()
+	}
+
+	// Fetch Enhanced Monitoring OS metrics of instances
+	if c.configuration.CollectOSMetrics && c.freshness.isStale(collectorOSMetrics, c.configuration.OSMetricsRefreshInterval, now) {
+		instances := getEnhancedMonitoringInstances(rdsMetrics.Instances)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.getOSMetrics(ctx, instances)
+		}()
+	}
+
+	// Fetch engine support lifecycle for instances. New instances are fetched immediately
+	if c.configuration.CollectEngineSupport {
+		if c.freshness.isStale(collectorEngineSupport, c.configuration.EngineSupportRefreshInterval, now) || !c.hasEngineSupportMetrics(rdsMetrics.Instances) {
+			if c.isBudgetAvailable(collectorEngineSupport, awsapi.RDSService, c.countUncachedEngines(rdsMetrics.Instances)) {
+				c.getEngineSupportMetrics(ctx, rdsMetrics.Instances)
+			}
+		}
+	}
+
+	// Wait for all go routines to finish
+	wg.Wait()
+
+	return nil
+}
+
+// isBudgetAvailable returns true if the AWS API budget allows the low priority collector to run
+// Otherwise, the collector is skipped until the next refresh and its previous metrics are kept
+func (c *rdsCollector) isBudgetAvailable(collector string, service string, calls int) bool {
+	if c.configuration.APIBudget == nil || c.configuration.APIBudget.Available(c.awsAccountID, c.awsRegion, service, calls) {
+		return true
+	}
+
+	c.logger.Warn("AWS API budget is exhausted, skip low priority collector", "collector", collector, "api", service)
+	c.freshness.markSkipped(collector)
+
+	return false
+}
+
+// getCloudWatchInstances returns instances to collect CloudWatch metrics for, sorted by identifier
+// Instance classes without EC2 information (eg. EC2 metrics not fetched yet) are considered as having burstable EBS bandwidth
+func getCloudWatchInstances(instances map[string]rds.RdsInstanceMetrics, ec2Metrics ec2.Metrics) []cloudwatch.Resource {
+	resources := make([]cloudwatch.Resource, 0, len(instances))
+
+	for dbIdentifier, instance := range instances {
+		burstableEBS := true
+		if instanceType, found := ec2Metrics.Instances[instance.DBInstanceClass]; found {
+			burstableEBS = instanceType.BurstableEBS
+		}
+
+		resources = append(resources, cloudwatch.Resource{
+			Identifier:    dbIdentifier,
+			Engine:        instance.Engine,
+			InstanceClass: instance.DBInstanceClass,
+			StorageType:   instance.StorageType,
+			BurstableEBS:  burstableEBS,
+		})
+	}
+
+	sortResources(resources)
+
+	return resources
+}
+
+// getAuroraClusters returns Aurora clusters to collect CloudWatch metrics for, sorted by identifier
+// Other clusters (eg. Multi-AZ DB clusters) don't have cluster-level CloudWatch metrics
+func getAuroraClusters(clusters map[string]rds.ClusterMetrics) []cloudwatch.Resource {
+	resources := make([]cloudwatch.Resource, 0, len(clusters))
+
+	for clusterIdentifier, cluster := range clusters {
+		if strings.HasPrefix(cluster.Engine, "aurora") {
+			resources = append(resources, cloudwatch.Resource{Identifier: clusterIdentifier, Engine: cluster.Engine})
+		}
+	}
+
+	sortResources(resources)
+
+	return resources
+}
+
+// getCloudWatchProxies returns proxies and their instance targets to collect CloudWatch metrics for, sorted by identifier
+// Other targets (eg. tracked Aurora clusters) don't have CloudWatch metrics
+func getCloudWatchProxies(proxies map[string]rds.ProxyMetrics) (proxyResources []cloudwatch.Resource, targetResources []cloudwatch.Resource) {
+	for proxyName, proxy := range proxies {
+		proxyResources = append(proxyResources, cloudwatch.Resource{Identifier: proxyName})
+
+		for _, target := range proxy.Targets {
+			if target.IsInstance() {
+				targetResources = append(targetResources, cloudwatch.NewProxyTargetResource(proxyName, target.TargetGroup, target.DBIdentifier))
+			}
+		}
+	}
+
+	sortResources(proxyResources)
+	sortResources(targetResources)
+
+	return proxyResources, targetResources
+}
+
+// getPerformanceInsightsInstances returns instances with Performance Insights enabled, sorted by identifier
+func getPerformanceInsightsInstances(instances map[string]rds.RdsInstanceMetrics) []performanceinsights.Instance {
+	var piInstances []performanceinsights.Instance
+
+	for dbIdentifier, instance := range instances {
+		if instance.PerformanceInsightsEnabled && instance.DbiResourceID != "" {
+			piInstances = append(piInstances, performanceinsights.Instance{DBIdentifier: dbIdentifier, DbiResourceID: instance.DbiResourceID})
+		}
+	}
+
+	slices.SortFunc(piInstances, func(a, b performanceinsights.Instance) int {
+		return strings.Compare(a.DBIdentifier, b.DBIdentifier)
+	})
+
+	return piInstances
+}
+
+func sortResources(resources []cloudwatch.Resource) {
+	slices.SortFunc(resources, func(a, b cloudwatch.Resource) int {
+		return strings.Compare(a.Identifier, b.Identifier)
+	})
+}
+
+// estimateQuotasAPICalls returns the number of AWS API calls of the next quotas fetch from the pages listed by the last fetch
+func (c *rdsCollector) estimateQuotasAPICalls() int {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.quotasAPICalls == 0 {
+		return defaultQuotasAPICalls
+	}
+
+	return c.quotasAPICalls
+}
+
+// countUncachedEngines returns the number of distinct engines of instances without cached engine lifecycles
+// Each of them requires an AWS API call, while cached engines don't call AWS API
+func (c *rdsCollector) countUncachedEngines(instances map[string]rds.RdsInstanceMetrics) int {
+	engines := make(map[string]bool)
+
+	for _, instance := range instances {
+		if !c.engineSupportService.IsCached(instance.Engine) {
+			engines[instance.Engine] = true
+		}
+	}
+
+	return len(engines)
+}
+
+// update applies fetch results to counters and metrics
+// Fetchers run concurrently, so they must only modify the collector state through update
+func (c *rdsCollector) update(apply func(counters *counters, metrics *metrics)) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	apply(&c.counters, &c.metrics)
+}
+
+func (c *rdsCollector) getRDSMetrics(ctx context.Context) error {
 	c.logger.Debug("get RDS metrics")
 
-	rdsFetcher := rds.NewFetcher(c.ctx, c.rdsClient, c.tagClient, c.logger, rds.Configuration{
+	start := time.Now()
+
+	var previousInstances map[string]rds.RdsInstanceMetrics
+
+	c.update(func(_ *counters, metrics *metrics) {
+		previousInstances = metrics.RDS.Instances
+	})
+
+	// Logs size requires one AWS API call per instance, the budget is checked for each page of described instances
+	// Once skipped, logs size of remaining pages is skipped too
+	skipLogsSize := false
+
+	rdsFetcher := rds.NewFetcher(ctx, c.rdsClient, c.tagClient, c.logger, rds.Configuration{
 		CollectLogsSize:           c.configuration.CollectLogsSize,
 		CollectServerlessLogsSize: c.configuration.CollectServerlessLogsSize,
 		CollectMaintenances:       c.configuration.CollectMaintenances,
 		CollectClusterMetrics:     c.configuration.CollectClusterMetrics,
 		TagSelections:             c.configuration.TagSelections,
+		LogsSizeBudget: func(calls int) bool {
+			skipLogsSize = skipLogsSize || !c.isBudgetAvailable(collectorLogsSize, awsapi.RDSService, calls)
+
+			return !skipLogsSize
+		},
 	})
 
 	rdsMetrics, err := rdsFetcher.GetInstancesMetrics()
+	c.freshness.markResult(collectorRDS, err == nil, start)
+
 	if err != nil {
 		return fmt.Errorf("can't fetch RDS metrics: %w", err)
 	}
 
-	c.metrics.RDS = rdsMetrics
-	c.counters.RDSAPIcalls += rdsFetcher.GetStatistics().RdsAPICall
-	c.counters.TagAPICalls += rdsFetcher.GetStatistics().TagAPICall
+	// Keep previous logs size of instances when logs size collection is skipped
+	if skipLogsSize {
+		for dbidentifier, instance := range rdsMetrics.Instances {
+			if previousInstance, found := previousInstances[dbidentifier]; found && instance.LogFilesSize == nil {
+				instance.LogFilesSize = previousInstance.LogFilesSize
+				rdsMetrics.Instances[dbidentifier] = instance
+			}
+		}
+	}
+
+	c.update(func(_ *counters, metrics *metrics) {
+		metrics.RDS = rdsMetrics
+	})
 	c.logger.Debug("RDS metrics fetched")
 
-	// Compute uniq instances identifiers and instance types
-	instanceIdentifiers, instanceTypes := getUniqTypeAndIdentifiers(rdsMetrics.Instances)
+	return nil
+}
 
-	// Fetch EC2 Metrics for instance types
-	if c.configuration.CollectInstanceTypes && len(instanceTypes) > 0 {
-		go c.getEC2Metrics(c.EC2Client, instanceTypes)
-		c.wg.Add(1)
+// Refresh queries AWS APIs and replaces the snapshot served by Collect
+// Concurrent calls wait for and share the result of the in-flight collection
+func (c *rdsCollector) Refresh(ctx context.Context) error {
+	return c.coalescer.do(ctx, c.refresh)
+}
+
+// refresh is bounded by ScrapeTimeout through the coalescer
+func (c *rdsCollector) refresh(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "collect-metrics")
+	defer span.End()
+
+	span.SetAttributes(trace.AWSAccountID(c.awsAccountID), trace.AWSRegion(c.awsRegion))
+
+	err := c.fetchMetrics(ctx)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("can't scrape metrics: %s", err))
+
+		span.SetStatus(codes.Error, "failed to get metrics")
+		span.RecordError(err)
 	}
 
-	// Fetch Cloudwatch metrics for instances
-	if c.configuration.CollectInstanceMetrics {
-		go c.getCloudwatchMetrics(c.cloudWatchClient, instanceIdentifiers)
-		c.wg.Add(1)
+	newSnapshot := snapshot{
+		ready:       true,
+		err:         err,
+		lastSuccess: c.freshness.lastSuccesses(),
+		lastResult:  c.freshness.lastResults(),
+		skipped:     c.freshness.skippedCounts(),
 	}
 
-	// Wait for all go routines to finish
-	c.wg.Wait()
+	c.update(func(counters *counters, metrics *metrics) {
+		newSnapshot.counters = *counters
+		newSnapshot.metrics = *metrics
+	})
 
-	return nil
+	c.snapshotMutex.Lock()
+	c.snapshot = newSnapshot
+	c.snapshotMutex.Unlock()
+
+	return err
+}
+
+// Run refreshes metrics in background every RefreshInterval until the context is cancelled
+func (c *rdsCollector) Run(ctx context.Context) {
+	refreshPeriodically(ctx, c.configuration.RefreshInterval, c.Refresh)
 }
 
-func (c *rdsCollector) getCloudwatchMetrics(client cloudwatch.CloudWatchClient, instanceIdentifiers []string) {
-	defer c.wg.Done()
+// refreshPeriodically calls refresh every interval until the context is cancelled
+func refreshPeriodically(ctx context.Context, interval time.Duration, refresh func(context.Context) error) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = refresh(ctx) // Errors are logged and exposed through the up metric
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshOnScrape calls refresh during a scrape, bounded by the scrape timeout
+// Prometheus Collect doesn't provide the scrape request context
+func refreshOnScrape(timeout time.Duration, refresh func(context.Context) error) {
+	ctx := context.Background()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	_ = refresh(ctx) // Errors are logged and exposed through the up metric
+}
+
+func (c *rdsCollector) getSnapshot() snapshot {
+	c.snapshotMutex.RLock()
+	defer c.snapshotMutex.RUnlock()
+
+	return c.snapshot
+}
+
+func (c *rdsCollector) getCloudwatchMetrics(ctx context.Context, client cloudwatch.CloudWatchClient, instances []cloudwatch.Resource) {
+	start := time.Now()
+
 	c.logger.Debug("fetch cloudwatch metrics")
 
-	_, span := tracer.Start(c.ctx, "collect-cloudwatch-metrics")
+	ctx, span := tracer.Start(ctx, "collect-cloudwatch-metrics")
 	defer span.End()
 
-	fetcher := cloudwatch.NewRDSFetcher(client, c.logger)
+	fetcher := cloudwatch.NewRDSFetcher(ctx, client, c.logger, c.cloudwatchDefinitions)
 
-	metrics, err := fetcher.GetRDSInstanceMetrics(instanceIdentifiers)
+	// Metrics of successful chunks of queries are kept when some chunks fail
+	cloudwatchMetrics, err := fetcher.GetRDSInstanceMetrics(instances)
 	if err != nil {
-		c.counters.Errors++
+		c.logger.Error(fmt.Sprintf("can't fetch CloudWatch metrics: %s", err))
 	}
 
-	c.counters.CloudwatchAPICalls += fetcher.GetStatistics().CloudWatchAPICall
-	c.metrics.CloudwatchInstances = metrics
+	c.freshness.markResult(collectorCloudWatch, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		counters.CloudwatchIncomplete += fetcher.GetStatistics().IncompleteResults
+		metrics.CloudwatchInstances = cloudwatchMetrics
+	})
 
-	c.logger.Debug("cloudwatch metrics fetched", "metrics", metrics)
+	c.logger.Debug("cloudwatch metrics fetched", "metrics", cloudwatchMetrics)
 }
 
-func (c *rdsCollector) getUsagesMetrics(client cloudwatch.CloudWatchClient) {
-	defer c.wg.Done()
+func (c *rdsCollector) getCloudwatchClusterMetrics(ctx context.Context, client cloudwatch.CloudWatchClient, clusters []cloudwatch.Resource) {
+	start := time.Now()
+
+	c.logger.Debug("fetch cloudwatch cluster metrics")
+
+	ctx, span := tracer.Start(ctx, "collect-cloudwatch-cluster-metrics")
+	defer span.End()
+
+	fetcher := cloudwatch.NewRDSFetcher(ctx, client, c.logger, c.cloudwatchClusterDefinitions)
+
+	// Metrics of successful chunks of queries are kept when some chunks fail
+	cloudwatchMetrics, err := fetcher.GetRDSClusterMetrics(clusters)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("can't fetch CloudWatch cluster metrics: %s", err))
+	}
+
+	c.freshness.markResult(collectorCloudWatchClusters, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		counters.CloudwatchIncomplete += fetcher.GetStatistics().IncompleteResults
+		metrics.CloudwatchClusters = cloudwatchMetrics
+	})
+
+	c.logger.Debug("cloudwatch cluster metrics fetched", "metrics", cloudwatchMetrics)
+}
+
+func (c *rdsCollector) getCloudwatchProxyMetrics(ctx context.Context, client cloudwatch.CloudWatchClient, proxies []cloudwatch.Resource, targets []cloudwatch.Resource) {
+	start := time.Now()
+
+	c.logger.Debug("fetch cloudwatch proxy metrics")
+
+	ctx, span := tracer.Start(ctx, "collect-cloudwatch-proxy-metrics")
+	defer span.End()
+
+	proxyFetcher := cloudwatch.NewRDSFetcher(ctx, client, c.logger, cloudwatch.DefaultProxyMetricDefinitions())
+	targetFetcher := cloudwatch.NewRDSFetcher(ctx, client, c.logger, cloudwatch.DefaultProxyTargetMetricDefinitions())
+
+	// Metrics of successful chunks of queries are kept when some chunks fail
+	proxyMetrics, proxyErr := proxyFetcher.GetRDSProxyMetrics(proxies)
+	targetMetrics, targetErr := targetFetcher.GetRDSProxyTargetMetrics(targets)
+
+	err := errors.Join(proxyErr, targetErr)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("can't fetch CloudWatch proxy metrics: %s", err))
+	}
+
+	cloudwatchMetrics := cloudwatch.CloudWatchMetrics{
+		Proxies:      proxyMetrics.Proxies,
+		ProxyTargets: targetMetrics.ProxyTargets,
+	}
+
+	c.freshness.markResult(collectorCloudWatchProxies, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		counters.CloudwatchIncomplete += proxyFetcher.GetStatistics().IncompleteResults + targetFetcher.GetStatistics().IncompleteResults
+		metrics.CloudwatchProxies = cloudwatchMetrics
+	})
+
+	c.logger.Debug("cloudwatch proxy metrics fetched", "metrics", cloudwatchMetrics)
+}
+
+func (c *rdsCollector) getProxiesMetrics(ctx context.Context) {
+	start := time.Now()
+
+	c.logger.Debug("fetch RDS proxies")
+
+	fetcher := rds.NewFetcher(ctx, c.rdsClient, c.tagClient, c.logger, rds.Configuration{})
+
+	proxies, err := fetcher.GetProxiesMetrics()
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("can't fetch RDS proxies: %s", err))
+	}
+
+	c.freshness.markResult(collectorProxies, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		metrics.Proxies = proxies
+	})
+
+	c.logger.Debug("RDS proxies fetched", "proxies", proxies)
+}
+
+func (c *rdsCollector) getPerformanceInsightsMetrics(ctx context.Context, instances []performanceinsights.Instance) {
+	start := time.Now()
+
+	c.logger.Debug("fetch Performance Insights metrics")
+
+	fetcher := performanceinsights.NewFetcher(ctx, c.performanceInsightsClient, c.logger, c.configuration.PerformanceInsightsTopN)
+
+	// Metrics of successful instances are kept when some instances fail
+	performanceInsightsMetrics, err := fetcher.GetInstancesMetrics(instances)
+	if err != nil {
+		c.logger.Error(fmt.Sprintf("can't fetch Performance Insights metrics: %s", err))
+	}
+
+	c.freshness.markResult(collectorPerformanceInsights, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		metrics.PerformanceInsights = performanceInsightsMetrics
+	})
+
+	c.logger.Debug("Performance Insights metrics fetched", "metrics", performanceInsightsMetrics)
+}
+
+func (c *rdsCollector) getUsagesMetrics(ctx context.Context, client cloudwatch.CloudWatchClient) {
+	start := time.Now()
+
 	c.logger.Debug("fetch usage metrics")
 
-	fetcher := cloudwatch.NewUsageFetcher(c.ctx, client, c.logger)
+	// AWS/Usage calls are labelled "usage" in rds_api_call_total, distinct from CloudWatch calls of instance metrics
+	fetcher := cloudwatch.NewUsageFetcher(awsapi.WithAPIName(ctx, awsapi.UsageAPI), client, c.logger)
 
-	metrics, err := fetcher.GetUsageMetrics()
+	usageMetrics, err := fetcher.GetUsageMetrics()
 	if err != nil {
-		c.counters.Errors++
 		c.logger.Error(fmt.Sprintf("can't fetch usage metrics: %s", err))
 	}
 
-	c.counters.UsageAPIcalls += fetcher.GetStatistics().CloudWatchAPICall
-	c.metrics.CloudWatchUsage = metrics
+	c.freshness.markResult(collectorUsage, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		metrics.CloudWatchUsage = usageMetrics
+	})
 
-	c.logger.Debug("usage metrics fetched", "metrics", metrics)
+	c.logger.Debug("usage metrics fetched", "metrics", usageMetrics)
 }
 
-func (c *rdsCollector) getEC2Metrics(client ec2.EC2Client, instanceTypes []string) {
-	defer c.wg.Done()
+func (c *rdsCollector) getEC2Metrics(ctx context.Context, client ec2.EC2Client, instanceTypes []string) {
+	start := time.Now()
+
 	c.logger.Debug("fetch EC2 metrics")
 
-	fetcher := ec2.NewFetcher(c.ctx, client)
+	fetcher := ec2.NewFetcher(ctx, client)
 
-	metrics, err := fetcher.GetDBInstanceTypeInformation(instanceTypes)
+	ec2Metrics, err := fetcher.GetDBInstanceTypeInformation(instanceTypes)
 	if err != nil {
-		c.counters.Errors++
 		c.logger.Error(fmt.Sprintf("can't fetch EC2 metrics: %s", err))
 	}
 
-	c.counters.EC2APIcalls += fetcher.GetStatistics().EC2ApiCall
-	c.metrics.EC2 = metrics
+	c.freshness.markResult(collectorEC2, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		} else {
+			c.ec2InstanceTypes = instanceTypes
+		}
+
+		metrics.EC2 = ec2Metrics
+	})
 
-	c.logger.Debug("EC2 metrics fetched", "metrics", metrics)
+	c.logger.Debug("EC2 metrics fetched", "metrics", ec2Metrics)
 }
 
-func (c *rdsCollector) getQuotasMetrics(client servicequotas.ServiceQuotasClient) {
-	defer c.wg.Done()
+func (c *rdsCollector) getQuotasMetrics(ctx context.Context, client servicequotas.ServiceQuotasClient) {
+	start := time.Now()
 
-	ctx, span := tracer.Start(c.ctx, "collect-quota-metrics")
+	ctx, span := tracer.Start(ctx, "collect-quota-metrics")
 	defer span.End()
 
 	c.logger.Debug("fetch quotas")
 
 	fetcher := servicequotas.NewFetcher(ctx, client, c.logger)
 
-	metrics, err := fetcher.GetRDSQuotas()
+	quotasMetrics, err := fetcher.GetRDSQuotas()
 	if err != nil {
-		c.counters.Errors++
 		c.logger.Error(fmt.Sprintf("can't fetch service quota metrics: %s", err))
 		span.SetStatus(codes.Error, "can't fetch service quota metrics")
 		span.RecordError(err)
 	}
 
-	c.counters.ServiceQuotasAPICalls += fetcher.GetStatistics().UsageAPICall
-	c.metrics.ServiceQuota = metrics
+	c.freshness.markResult(collectorServiceQuotas, err == nil, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		if err != nil {
+			counters.Errors++
+		}
+
+		metrics.ServiceQuota = quotasMetrics
+		c.quotasAPICalls = int(fetcher.GetStatistics().UsageAPICall)
+	})
 
 	span.SetStatus(codes.Ok, "quota fetched")
 }
@@ -602,37 +1279,69 @@ func (c *rdsCollector) getInstanceTagLabels(dbidentifier string, instance rds.Rd
 }
 
 func (c *rdsCollector) Collect(ch chan<- prometheus.Metric) {
-	ch <- prometheus.MustNewConstMetric(c.exporterBuildInformation, prometheus.GaugeValue, 1, build.Version, build.CommitSHA, build.Date)
-	ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, c.counters.Errors)
+	// Query AWS APIs on each scrape when background refresh is disabled
+	if c.configuration.RefreshInterval <= 0 {
+		refreshOnScrape(c.configuration.ScrapeTimeout, func(ctx context.Context) error {
+			return c.coalescer.doScrape(ctx, c.refresh)
+		})
+	}
 
-	var span trace.Span
+	snapshot := c.getSnapshot()
 
-	c.ctx, span = tracer.Start(context.TODO(), "collect-metrics")
-	defer span.End()
+	c.collectExporterMetrics(ch, snapshot.counters.Errors, c.coalescer.coalescedCount(), snapshot.healthy())
+	c.collectSnapshot(ch, snapshot)
+}
 
-	// Get all metrics
-	err := c.fetchMetrics()
-	if err != nil {
-		c.logger.Error(fmt.Sprintf("can't scrape metrics: %s", err))
+// collectExporterMetrics emits metrics describing the exporter itself
+func (c *rdsCollector) collectExporterMetrics(ch chan<- prometheus.Metric, errorsCount float64, coalescedScrapesCount float64, healthy bool) {
+	ch <- prometheus.MustNewConstMetric(c.exporterBuildInformation, prometheus.GaugeValue, 1, build.Version, build.CommitSHA, build.Date)
+	ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, errorsCount)
+	ch <- prometheus.MustNewConstMetric(c.coalescedScrapes, prometheus.CounterValue, coalescedScrapesCount)
+
+	if !healthy {
 		// Mark exporter as down
 		ch <- prometheus.MustNewConstMetric(c.up, prometheus.CounterValue, exporterDownStatusCode)
 
-		span.SetStatus(codes.Error, "failed to get metrics")
-		span.RecordError(err)
-
 		return
 	}
 
-	span.End()
-
 	ch <- prometheus.MustNewConstMetric(c.up, prometheus.CounterValue, exporterUpStatusCode)
+}
+
+// collectSnapshot emits AWS metrics of the snapshot, including metrics of a failed refresh
+func (c *rdsCollector) collectSnapshot(ch chan<- prometheus.Metric, snapshot snapshot) {
+	targetUp := exporterDownStatusCode
+	if snapshot.healthy() {
+		targetUp = exporterUpStatusCode
+	}
 
-	// API metrics
-	ch <- prometheus.MustNewConstMetric(c.apiCall, prometheus.CounterValue, c.counters.RDSAPIcalls, c.awsAccountID, c.awsRegion, "rds")
-	ch <- prometheus.MustNewConstMetric(c.apiCall, prometheus.CounterValue, c.counters.TagAPICalls, c.awsAccountID, c.awsRegion, "tag")
+	ch <- prometheus.MustNewConstMetric(c.targetUp, prometheus.GaugeValue, targetUp, c.awsAccountID, c.awsRegion)
+
+	for collector, lastSuccess := range snapshot.lastSuccess {
+		ch <- prometheus.MustNewConstMetric(c.collectorLastSuccess, prometheus.GaugeValue, float64(lastSuccess.Unix()), c.awsAccountID, c.awsRegion, collector)
+	}
+
+	for collector, result := range snapshot.lastResult {
+		success := exporterDownStatusCode
+		if result.success {
+			success = exporterUpStatusCode
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.collectorSuccess, prometheus.GaugeValue, success, c.awsAccountID, c.awsRegion, collector)
+		ch <- prometheus.MustNewConstMetric(c.collectorDuration, prometheus.GaugeValue, result.duration.Seconds(), c.awsAccountID, c.awsRegion, collector)
+	}
+
+	for collector, skipped := range snapshot.skipped {
+		ch <- prometheus.MustNewConstMetric(c.collectorSkipped, prometheus.CounterValue, skipped, c.awsAccountID, c.awsRegion, collector)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.cloudwatchIncompleteResults, prometheus.CounterValue, snapshot.counters.CloudwatchIncomplete, c.awsAccountID, c.awsRegion)
+
+	// Metrics collected so far are exposed even when the refresh failed (eg. scrape timeout)
+	// Failed collectors are reported by rds_exporter_collector_success and rds_exporter_target_up
 
 	// Cluster metrics
-	for clusterIdentifier, cluster := range c.metrics.RDS.Clusters {
+	for clusterIdentifier, cluster := range snapshot.metrics.RDS.Clusters {
 		ch <- prometheus.MustNewConstMetric(
 			c.clusterInformation,
 			prometheus.GaugeValue,
@@ -649,8 +1358,17 @@ func (c *rdsCollector) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(c.clusterServerLessMinACU, prometheus.GaugeValue, cluster.ServerLessMinACU, c.awsAccountID, c.awsRegion, clusterIdentifier)
 	}
 
+	// Proxy metrics
+	for proxyName, proxy := range snapshot.metrics.Proxies {
+		ch <- prometheus.MustNewConstMetric(c.proxyInformation, prometheus.GaugeValue, 1, c.awsAccountID, c.awsRegion, proxyName, proxy.EngineFamily, proxy.Status, strconv.FormatBool(proxy.RequireTLS), proxy.Arn)
+
+		for _, target := range proxy.Targets {
+			ch <- prometheus.MustNewConstMetric(c.proxyTargetHealth, prometheus.GaugeValue, float64(target.State), c.awsAccountID, c.awsRegion, proxyName, target.TargetGroup, target.DBIdentifier, target.Type, target.Role, target.Reason)
+		}
+	}
+
 	// Instance metrics
-	for dbidentifier, instance := range c.metrics.RDS.Instances {
+	for dbidentifier, instance := range snapshot.metrics.RDS.Instances {
 		ch <- prometheus.MustNewConstMetric(
 			c.allocatedStorage,
 			prometheus.GaugeValue,
@@ -700,7 +1418,7 @@ func (c *rdsCollector) Collect(ch chan<- prometheus.Metric) {
 		storageThroughput := float64(instance.StorageThroughput)
 
 		// RDS disk performance are limited by the EBS volume attached the RDS instance
-		if ec2Metrics, ok := c.metrics.EC2.Instances[instance.DBInstanceClass]; ok {
+		if ec2Metrics, ok := snapshot.metrics.EC2.Instances[instance.DBInstanceClass]; ok {
 			if instance.MaxIops > 0 {
 				maxIops = min(instance.MaxIops, int64(ec2Metrics.BaselineIOPS))
 			} else {
@@ -723,7 +1441,7 @@ func (c *rdsCollector) Collect(ch chan<- prometheus.Metric) {
 		}
 
 		// Network throughput from EC2 instance type
-		if ec2Metrics, ok := c.metrics.EC2.Instances[instance.DBInstanceClass]; ok {
+		if ec2Metrics, ok := snapshot.metrics.EC2.Instances[instance.DBInstanceClass]; ok {
 			if ec2Metrics.BaselineNetworkBandwidth > 0 {
 				ch <- prometheus.MustNewConstMetric(c.maxNetworkThroughput, prometheus.GaugeValue, ec2Metrics.BaselineNetworkBandwidth, c.awsAccountID, c.awsRegion, dbidentifier)
 			}
@@ -732,8 +1450,9 @@ func (c *rdsCollector) Collect(ch chan<- prometheus.Metric) {
 		if c.configuration.CollectInstanceTags {
 			names, values := c.getInstanceTagLabels(dbidentifier, instance)
 
-			c.instanceTags = prometheus.NewDesc("rds_instance_tags", "AWS tags attached to the instance", names, nil)
-			ch <- prometheus.MustNewConstMetric(c.instanceTags, prometheus.GaugeValue, 0, values...)
+			// Tags differ between instances, so each instance has its own descriptor
+			instanceTags := prometheus.NewDesc("rds_instance_tags", "AWS tags attached to the instance", names, nil)
+			ch <- prometheus.MustNewConstMetric(instanceTags, prometheus.GaugeValue, 0, values...)
 		}
 
 		if instance.CertificateValidTill != nil {
@@ -750,174 +1469,257 @@ func (c *rdsCollector) Collect(ch chan<- prometheus.Metric) {
 
 		// Engine support metrics for PostgreSQL instances
 		if c.configuration.CollectEngineSupport {
-			c.collectEngineSupportMetrics(ch, dbidentifier, instance.Engine, instance.EngineVersion)
+			if engineSupport, ok := snapshot.metrics.EngineSupport[dbidentifier]; ok {
+				c.collectEngineSupportMetrics(ch, dbidentifier, instance.Engine, instance.EngineVersion, engineSupport)
+			}
 		}
 	}
 
 	// Cloudwatch metrics
-	ch <- prometheus.MustNewConstMetric(c.apiCall, prometheus.CounterValue, c.counters.CloudwatchAPICalls, c.awsAccountID, c.awsRegion, "cloudwatch")
+	now := time.Now()
 
-	for dbidentifier, instance := range c.metrics.CloudwatchInstances.Instances {
-		if instance.DatabaseConnections != nil {
-			ch <- prometheus.MustNewConstMetric(c.databaseConnections, prometheus.GaugeValue, *instance.DatabaseConnections, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+	for dbidentifier, instance := range snapshot.metrics.CloudwatchInstances.Instances {
+		c.collectCloudWatchMetrics(ch, c.cloudwatchMetrics, c.cloudwatchDatapointAge, []string{dbidentifier}, instance, now)
+	}
 
-		if instance.FreeStorageSpace != nil {
-			ch <- prometheus.MustNewConstMetric(c.freeStorageSpace, prometheus.GaugeValue, *instance.FreeStorageSpace, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+	for clusterIdentifier, cluster := range snapshot.metrics.CloudwatchClusters.Clusters {
+		c.collectCloudWatchMetrics(ch, c.cloudwatchClusterMetrics, c.cloudwatchClusterDatapointAge, []string{clusterIdentifier}, cluster, now)
+	}
 
-		if instance.FreeableMemory != nil {
-			ch <- prometheus.MustNewConstMetric(c.freeableMemory, prometheus.GaugeValue, *instance.FreeableMemory, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+	for proxyName, proxy := range snapshot.metrics.CloudwatchProxies.Proxies {
+		c.collectCloudWatchMetrics(ch, c.cloudwatchProxyMetrics, c.cloudwatchProxyDatapointAge, []string{proxyName}, proxy, now)
+	}
 
-		if instance.MaximumUsedTransactionIDs != nil {
-			ch <- prometheus.MustNewConstMetric(c.maximumUsedTransactionIDs, prometheus.GaugeValue, *instance.MaximumUsedTransactionIDs, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+	for proxyName, proxy := range snapshot.metrics.Proxies {
+		for _, target := range proxy.Targets {
+			datapoints, found := snapshot.metrics.CloudwatchProxies.ProxyTargets[cloudwatch.ProxyTargetIdentifier(proxyName, target.TargetGroup, target.DBIdentifier)]
+			if !found {
+				continue
+			}
 
-		if instance.ReadThroughput != nil {
-			ch <- prometheus.MustNewConstMetric(c.readThroughput, prometheus.GaugeValue, *instance.ReadThroughput, c.awsAccountID, c.awsRegion, dbidentifier)
+			c.collectCloudWatchMetrics(ch, c.cloudwatchProxyTargetMetrics, c.cloudwatchProxyTargetDatapointAge, []string{proxyName, target.TargetGroup, target.DBIdentifier}, datapoints, now)
 		}
+	}
 
-		if instance.ReplicaLag != nil {
-			ch <- prometheus.MustNewConstMetric(c.replicaLag, prometheus.GaugeValue, *instance.ReplicaLag, c.awsAccountID, c.awsRegion, dbidentifier)
+	// Performance Insights metrics
+	for dbidentifier, instance := range snapshot.metrics.PerformanceInsights.Instances {
+		for _, waitEvent := range instance.WaitEvents {
+			ch <- prometheus.MustNewConstMetric(c.performanceInsightsWaitEventLoad, prometheus.GaugeValue, waitEvent.Value, c.awsAccountID, c.awsRegion, dbidentifier, waitEvent.Type, waitEvent.Name)
 		}
 
-		if instance.ReplicationSlotDiskUsage != nil {
-			ch <- prometheus.MustNewConstMetric(c.replicationSlotDiskUsage, prometheus.GaugeValue, *instance.ReplicationSlotDiskUsage, c.awsAccountID, c.awsRegion, dbidentifier)
+		for _, sql := range instance.SQL {
+			ch <- prometheus.MustNewConstMetric(c.performanceInsightsSQLLoad, prometheus.GaugeValue, sql.Value, c.awsAccountID, c.awsRegion, dbidentifier, sql.ID, sql.Statement)
 		}
+	}
 
-		if instance.SwapUsage != nil {
-			ch <- prometheus.MustNewConstMetric(c.swapUsage, prometheus.GaugeValue, *instance.SwapUsage, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+	// Enhanced Monitoring OS metrics
+	for dbidentifier, instance := range snapshot.metrics.OS.Instances {
+		c.collectOSMetrics(ch, dbidentifier, instance, now)
+	}
 
-		if instance.ReadIOPS != nil {
-			ch <- prometheus.MustNewConstMetric(c.readIOPS, prometheus.GaugeValue, *instance.ReadIOPS, c.awsAccountID, c.awsRegion, dbidentifier)
+	// usage metrics
+	if c.configuration.CollectUsages {
+		ch <- prometheus.MustNewConstMetric(c.usageAllocatedStorage, prometheus.GaugeValue, snapshot.metrics.CloudWatchUsage.AllocatedStorage, c.awsAccountID, c.awsRegion)
+		ch <- prometheus.MustNewConstMetric(c.usageDBInstances, prometheus.GaugeValue, snapshot.metrics.CloudWatchUsage.DBInstances, c.awsAccountID, c.awsRegion)
+		ch <- prometheus.MustNewConstMetric(c.usageManualSnapshots, prometheus.GaugeValue, snapshot.metrics.CloudWatchUsage.ManualSnapshots, c.awsAccountID, c.awsRegion)
+
+		for resource, usage := range snapshot.metrics.CloudWatchUsage.Resources {
+			// Allocated storage is exported in bytes by rds_usage_allocated_storage_bytes
+			if resource != cloudwatch.AllocatedStorageResource {
+				ch <- prometheus.MustNewConstMetric(c.usageResources, prometheus.GaugeValue, usage, c.awsAccountID, c.awsRegion, resource)
+			}
 		}
+	}
 
-		if instance.WriteIOPS != nil {
-			ch <- prometheus.MustNewConstMetric(c.writeIOPS, prometheus.GaugeValue, *instance.WriteIOPS, c.awsAccountID, c.awsRegion, dbidentifier)
+	// EC2 metrics
+	for instanceType, instance := range snapshot.metrics.EC2.Instances {
+		ch <- prometheus.MustNewConstMetric(c.instanceBaselineIops, prometheus.GaugeValue, float64(instance.BaselineIOPS), c.awsAccountID, c.awsRegion, instanceType)
+		ch <- prometheus.MustNewConstMetric(c.instanceBaselineThroughput, prometheus.GaugeValue, instance.BaselineThroughput, c.awsAccountID, c.awsRegion, instanceType)
+		ch <- prometheus.MustNewConstMetric(c.instanceMaximumIops, prometheus.GaugeValue, float64(instance.MaximumIops), c.awsAccountID, c.awsRegion, instanceType)
+		ch <- prometheus.MustNewConstMetric(c.instanceMaximumThroughput, prometheus.GaugeValue, instance.MaximumThroughput, c.awsAccountID, c.awsRegion, instanceType)
+		ch <- prometheus.MustNewConstMetric(c.instanceMemory, prometheus.GaugeValue, float64(instance.Memory), c.awsAccountID, c.awsRegion, instanceType)
+		ch <- prometheus.MustNewConstMetric(c.instanceVCPU, prometheus.GaugeValue, float64(instance.Vcpu), c.awsAccountID, c.awsRegion, instanceType)
+		ch <- prometheus.MustNewConstMetric(c.instanceBurstablePerformance, prometheus.GaugeValue, converter.BoolToFloat64(instance.BurstablePerformance), c.awsAccountID, c.awsRegion, instanceType)
+		ch <- prometheus.MustNewConstMetric(c.instanceBurstableEBS, prometheus.GaugeValue, converter.BoolToFloat64(instance.BurstableEBS), c.awsAccountID, c.awsRegion, instanceType)
+		if instance.BaselineNetworkBandwidth > 0 {
+			ch <- prometheus.MustNewConstMetric(c.instanceBaselineNetworkBandwidth, prometheus.GaugeValue, instance.BaselineNetworkBandwidth, c.awsAccountID, c.awsRegion, instanceType)
 		}
+	}
 
-		if instance.WriteThroughput != nil {
-			ch <- prometheus.MustNewConstMetric(c.writeThroughput, prometheus.GaugeValue, *instance.WriteThroughput, c.awsAccountID, c.awsRegion, dbidentifier)
+	// serviceQuotas metrics
+	if c.configuration.CollectQuotas {
+		ch <- prometheus.MustNewConstMetric(c.quotaDBInstances, prometheus.GaugeValue, snapshot.metrics.ServiceQuota.DBinstances, c.awsAccountID, c.awsRegion)
+		ch <- prometheus.MustNewConstMetric(c.quotaTotalStorage, prometheus.GaugeValue, snapshot.metrics.ServiceQuota.TotalStorage, c.awsAccountID, c.awsRegion)
+		ch <- prometheus.MustNewConstMetric(c.quotaMaxDBInstanceSnapshots, prometheus.GaugeValue, snapshot.metrics.ServiceQuota.ManualDBInstanceSnapshots, c.awsAccountID, c.awsRegion)
+
+		for resource, quota := range snapshot.metrics.ServiceQuota.Resources {
+			// Total storage quota is exported in bytes by rds_quota_total_storage_bytes
+			if resource != cloudwatch.AllocatedStorageResource {
+				ch <- prometheus.MustNewConstMetric(c.quotaResources, prometheus.GaugeValue, quota, c.awsAccountID, c.awsRegion, resource)
+			}
 		}
 
-		if instance.StorageNetworkReceiveThroughput != nil {
-			ch <- prometheus.MustNewConstMetric(c.storageNetworkReceiveThroughput, prometheus.GaugeValue, *instance.StorageNetworkReceiveThroughput, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+		for code, quota := range snapshot.metrics.ServiceQuota.Quotas {
+			if len(c.configuration.QuotaCodes) > 0 && !slices.Contains(c.configuration.QuotaCodes, code) {
+				continue
+			}
 
-		if instance.StorageNetworkTransmitThroughput != nil {
-			ch <- prometheus.MustNewConstMetric(c.storageNetworkTransmitThroughput, prometheus.GaugeValue, *instance.StorageNetworkTransmitThroughput, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+			adjustable := strconv.FormatBool(quota.Adjustable)
 
-		if instance.TransactionLogsDiskUsage != nil {
-			ch <- prometheus.MustNewConstMetric(c.transactionLogsDiskUsage, prometheus.GaugeValue, *instance.TransactionLogsDiskUsage, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+			if quota.Value != nil {
+				ch <- prometheus.MustNewConstMetric(c.quotaValue, prometheus.GaugeValue, *quota.Value, c.awsAccountID, c.awsRegion, code, quota.Name, adjustable, "applied")
+			}
 
-		if instance.DBLoad != nil {
-			ch <- prometheus.MustNewConstMetric(c.DBLoad, prometheus.GaugeValue, *instance.DBLoad, c.awsAccountID, c.awsRegion, dbidentifier)
+			if quota.DefaultValue != nil {
+				ch <- prometheus.MustNewConstMetric(c.quotaValue, prometheus.GaugeValue, *quota.DefaultValue, c.awsAccountID, c.awsRegion, code, quota.Name, adjustable, "default")
+			}
 		}
+	}
 
-		if instance.CPUUtilization != nil {
-			ch <- prometheus.MustNewConstMetric(c.cpuUtilisation, prometheus.GaugeValue, *instance.CPUUtilization, c.awsAccountID, c.awsRegion, dbidentifier)
+	// Quotas utilization of resources with both usage and quota
+	if c.configuration.CollectUsages && c.configuration.CollectQuotas {
+		for resource, usage := range snapshot.metrics.CloudWatchUsage.Resources {
+			quota, found := snapshot.metrics.ServiceQuota.Resources[resource]
+			if found && quota > 0 {
+				ch <- prometheus.MustNewConstMetric(c.quotaUtilization, prometheus.GaugeValue, usage/quota, c.awsAccountID, c.awsRegion, resource)
+			}
 		}
+	}
+}
 
-		if instance.DBLoadCPU != nil {
-			ch <- prometheus.MustNewConstMetric(c.dBLoadCPU, prometheus.GaugeValue, *instance.DBLoadCPU, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+func (c *rdsCollector) GetStatistics() counters {
+	return c.getSnapshot().counters
+}
 
-		if instance.DBLoadNonCPU != nil {
-			ch <- prometheus.MustNewConstMetric(c.dBLoadNonCPU, prometheus.GaugeValue, *instance.DBLoadNonCPU, c.awsAccountID, c.awsRegion, dbidentifier)
-		}
+func (c *rdsCollector) GetMetrics() metrics {
+	return c.getSnapshot().metrics
+}
+
+// getEngineSupportMetrics fetches engine support lifecycle metrics for instances
+func (c *rdsCollector) getEngineSupportMetrics(ctx context.Context, instances map[string]rds.RdsInstanceMetrics) {
+	engineSupport := make(map[string]rds.EngineSupportMetrics)
+
+	start := time.Now()
+	errorsCount := 0
+
+	for dbidentifier, instance := range instances {
+		// Stop when the scrape deadline is exceeded, remaining instances can't be fetched
+		if ctx.Err() != nil {
+			c.logger.Error("can't fetch engine support metrics", "reason", ctx.Err())
 
-		if instance.ServerlessDatabaseCapacity != nil {
-			ch <- prometheus.MustNewConstMetric(c.serverlessDatabaseCapacity, prometheus.GaugeValue, *instance.ServerlessDatabaseCapacity, c.awsAccountID, c.awsRegion, dbidentifier)
+			errorsCount++
+
+			break
 		}
 
-		if instance.NetworkReceiveThroughput != nil {
-			ch <- prometheus.MustNewConstMetric(c.networkReceiveThroughput, prometheus.GaugeValue, *instance.NetworkReceiveThroughput, c.awsAccountID, c.awsRegion, dbidentifier)
+		engine := instance.Engine
+		engineVersion := instance.EngineVersion
+
+		// Validate input parameters
+		if dbidentifier == "" || engine == "" || engineVersion == "" {
+			c.logger.Error("Invalid parameters for engine support metrics collection",
+				"dbidentifier", dbidentifier,
+				"engine", engine,
+				"engine_version", engineVersion)
+			errorsCount++
+
+			continue
 		}
 
-		if instance.NetworkTransmitThroughput != nil {
-			ch <- prometheus.MustNewConstMetric(c.networkTransmitThroughput, prometheus.GaugeValue, *instance.NetworkTransmitThroughput, c.awsAccountID, c.awsRegion, dbidentifier)
+		// Get engine support metrics
+		metrics, err := c.engineSupportService.GetEngineSupportMetrics(ctx, engine, engineVersion)
+		if err != nil {
+			// Log specific error details for debugging
+			c.logger.Error("Failed to get engine support metrics",
+				"dbidentifier", dbidentifier,
+				"engine", engine,
+				"engine_version", engineVersion,
+				"error", err)
+
+			// Check for specific error types to provide better monitoring
+			if strings.Contains(err.Error(), "AccessDenied") {
+				c.logger.Error("Access denied for engine support metrics - check IAM permissions",
+					"dbidentifier", dbidentifier,
+					"required_permission", "rds:DescribeDBMajorEngineVersions")
+			} else if awsapi.IsThrottle(err) {
+				c.logger.Error("AWS API rate limit exceeded for engine support metrics",
+					"dbidentifier", dbidentifier)
+			}
+
+			errorsCount++
+
+			continue
 		}
-	}
 
-	// usage metrics
-	if c.configuration.CollectUsages {
-		ch <- prometheus.MustNewConstMetric(c.apiCall, prometheus.CounterValue, c.counters.UsageAPIcalls, c.awsAccountID, c.awsRegion, "usage")
-		ch <- prometheus.MustNewConstMetric(c.usageAllocatedStorage, prometheus.GaugeValue, c.metrics.CloudWatchUsage.AllocatedStorage, c.awsAccountID, c.awsRegion)
-		ch <- prometheus.MustNewConstMetric(c.usageDBInstances, prometheus.GaugeValue, c.metrics.CloudWatchUsage.DBInstances, c.awsAccountID, c.awsRegion)
-		ch <- prometheus.MustNewConstMetric(c.usageManualSnapshots, prometheus.GaugeValue, c.metrics.CloudWatchUsage.ManualSnapshots, c.awsAccountID, c.awsRegion)
+		engineSupport[dbidentifier] = metrics
 	}
 
-	// EC2 metrics
-	ch <- prometheus.MustNewConstMetric(c.apiCall, prometheus.CounterValue, c.counters.EC2APIcalls, c.awsAccountID, c.awsRegion, "ec2")
-	for instanceType, instance := range c.metrics.EC2.Instances {
-		ch <- prometheus.MustNewConstMetric(c.instanceBaselineIops, prometheus.GaugeValue, float64(instance.BaselineIOPS), c.awsAccountID, c.awsRegion, instanceType)
-		ch <- prometheus.MustNewConstMetric(c.instanceBaselineThroughput, prometheus.GaugeValue, instance.BaselineThroughput, c.awsAccountID, c.awsRegion, instanceType)
-		ch <- prometheus.MustNewConstMetric(c.instanceMaximumIops, prometheus.GaugeValue, float64(instance.MaximumIops), c.awsAccountID, c.awsRegion, instanceType)
-		ch <- prometheus.MustNewConstMetric(c.instanceMaximumThroughput, prometheus.GaugeValue, instance.MaximumThroughput, c.awsAccountID, c.awsRegion, instanceType)
-		ch <- prometheus.MustNewConstMetric(c.instanceMemory, prometheus.GaugeValue, float64(instance.Memory), c.awsAccountID, c.awsRegion, instanceType)
-		ch <- prometheus.MustNewConstMetric(c.instanceVCPU, prometheus.GaugeValue, float64(instance.Vcpu), c.awsAccountID, c.awsRegion, instanceType)
-		if instance.BaselineNetworkBandwidth > 0 {
-			ch <- prometheus.MustNewConstMetric(c.instanceBaselineNetworkBandwidth, prometheus.GaugeValue, instance.BaselineNetworkBandwidth, c.awsAccountID, c.awsRegion, instanceType)
+	c.freshness.markResult(collectorEngineSupport, errorsCount == 0, start)
+	c.update(func(counters *counters, metrics *metrics) {
+		counters.Errors += float64(errorsCount)
+		metrics.EngineSupport = engineSupport
+	})
+}
+
+// hasEngineSupportMetrics returns true if engine support metrics are known for all instances
+func (c *rdsCollector) hasEngineSupportMetrics(instances map[string]rds.RdsInstanceMetrics) bool {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	for dbidentifier := range instances {
+		if _, found := c.metrics.EngineSupport[dbidentifier]; !found {
+			return false
 		}
 	}
 
-	// serviceQuotas metrics
-	if c.configuration.CollectQuotas {
-		ch <- prometheus.MustNewConstMetric(c.apiCall, prometheus.CounterValue, c.counters.ServiceQuotasAPICalls, c.awsAccountID, c.awsRegion, "servicequotas")
-		ch <- prometheus.MustNewConstMetric(c.quotaDBInstances, prometheus.GaugeValue, c.metrics.ServiceQuota.DBinstances, c.awsAccountID, c.awsRegion)
-		ch <- prometheus.MustNewConstMetric(c.quotaTotalStorage, prometheus.GaugeValue, c.metrics.ServiceQuota.TotalStorage, c.awsAccountID, c.awsRegion)
-		ch <- prometheus.MustNewConstMetric(c.quotaMaxDBInstanceSnapshots, prometheus.GaugeValue, c.metrics.ServiceQuota.ManualDBInstanceSnapshots, c.awsAccountID, c.awsRegion)
-	}
+	return true
 }
 
-func (c *rdsCollector) GetStatistics() counters {
-	return c.counters
-}
+// collectCloudWatchMetrics sends CloudWatch metrics of an instance, a cluster, a proxy or a proxy target and the age of their datapoints
+// identifiers are the values of the identifier labels of the descriptions
+func (c *rdsCollector) collectCloudWatchMetrics(ch chan<- prometheus.Metric, descriptions map[string]cloudwatchMetric, ageDescription *prometheus.Desc, identifiers []string, datapoints cloudwatch.RdsMetrics, now time.Time) {
+	// Oldest datapoint by Prometheus metric name, since metrics with several statistics have one datapoint per statistic
+	oldestDatapoints := make(map[string]time.Time)
 
-func (c *rdsCollector) GetMetrics() metrics {
-	return c.metrics
-}
+	for key, datapoint := range datapoints {
+		metric, found := descriptions[key.Name]
+		if !found {
+			continue
+		}
 
-// collectEngineSupportMetrics collects engine support metrics for PostgreSQL instances
-func (c *rdsCollector) collectEngineSupportMetrics(ch chan<- prometheus.Metric, dbidentifier, engine, engineVersion string) {
-	// Validate input parameters
-	if dbidentifier == "" || engine == "" || engineVersion == "" {
-		c.logger.Error("Invalid parameters for engine support metrics collection",
-			"dbidentifier", dbidentifier,
-			"engine", engine,
-			"engine_version", engineVersion)
-		c.counters.Errors++
-		return
-	}
+		labels := append([]string{c.awsAccountID, c.awsRegion}, identifiers...)
+		if metric.statisticLabel {
+			labels = append(labels, cloudwatch.StatisticLabel(key.Statistic))
+		}
 
-	// Get engine support metrics
-	metrics, err := c.engineSupportService.GetEngineSupportMetrics(c.ctx, engine, engineVersion)
-	if err != nil {
-		// Log specific error details for debugging
-		c.logger.Error("Failed to get engine support metrics",
-			"dbidentifier", dbidentifier,
-			"engine", engine,
-			"engine_version", engineVersion,
-			"error", err)
+		ch <- c.withDatapointTimestamp(prometheus.MustNewConstMetric(metric.desc, prometheus.GaugeValue, datapoint.Value, labels...), datapoint.Timestamp)
 
-		// Check for specific error types to provide better monitoring
-		if strings.Contains(err.Error(), "AccessDenied") {
-			c.logger.Error("Access denied for engine support metrics - check IAM permissions",
-				"dbidentifier", dbidentifier,
-				"required_permission", "rds:DescribeDBMajorEngineVersions")
-		} else if strings.Contains(err.Error(), "RequestLimitExceeded") || strings.Contains(err.Error(), "Throttling") {
-			c.logger.Error("AWS API rate limit exceeded for engine support metrics",
-				"dbidentifier", dbidentifier)
+		if datapoint.Timestamp.IsZero() {
+			continue
 		}
 
-		c.counters.Errors++
-		return
+		if oldest, found := oldestDatapoints[key.Name]; !found || datapoint.Timestamp.Before(oldest) {
+			oldestDatapoints[key.Name] = datapoint.Timestamp
+		}
 	}
 
+	for name, timestamp := range oldestDatapoints {
+		labels := append(append([]string{c.awsAccountID, c.awsRegion}, identifiers...), name)
+		ch <- prometheus.MustNewConstMetric(ageDescription, prometheus.GaugeValue, now.Sub(timestamp).Seconds(), labels...)
+	}
+}
+
+// withDatapointTimestamp attaches the CloudWatch datapoint timestamp to the metric when enabled
+func (c *rdsCollector) withDatapointTimestamp(metric prometheus.Metric, timestamp time.Time) prometheus.Metric {
+	if !c.configuration.CloudWatchTimestamps || timestamp.IsZero() {
+		return metric
+	}
+
+	return prometheus.NewMetricWithTimestamp(timestamp, metric)
+}
+
+// collectEngineSupportMetrics emits engine support metrics for an instance
+func (c *rdsCollector) collectEngineSupportMetrics(ch chan<- prometheus.Metric, dbidentifier, engine, engineVersion string, metrics rds.EngineSupportMetrics) {
 	// Log when no metrics are available (graceful handling)
 	if metrics.StandardSupportRemainingDays == nil && metrics.ExtendedSupportRemainingDays == nil {
 		c.logger.Debug("No engine support metrics available for instance",
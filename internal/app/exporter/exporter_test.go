@@ -1,15 +1,34 @@
 package exporter_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_cloudwatch_types "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	aws_ec2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	aws_pi "github.com/aws/aws-sdk-go-v2/service/pi"
+	aws_pi_types "github.com/aws/aws-sdk-go-v2/service/pi/types"
+	aws_rds_types "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	aws_servicequotas "github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/cloudwatch"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/exporter"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/servicequotas"
 	"github.com/qonto/prometheus-rds-exporter/internal/infra/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	cloudwatch_mock "github.com/qonto/prometheus-rds-exporter/internal/app/cloudwatch/mock"
 	ec2_mock "github.com/qonto/prometheus-rds-exporter/internal/app/ec2/mock"
+	enhancedmonitoring_mock "github.com/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring/mock"
+	performanceinsights_mock "github.com/qonto/prometheus-rds-exporter/internal/app/performanceinsights/mock"
 	rds_mock "github.com/qonto/prometheus-rds-exporter/internal/app/rds/mock"
 	servicequotas_mock "github.com/qonto/prometheus-rds-exporter/internal/app/servicequotas/mock"
 	converter "github.com/qonto/prometheus-rds-exporter/internal/app/unit"
@@ -38,17 +57,19 @@ func TestWithAllDisabledCollectors(t *testing.T) {
 		CollectUsages:          false,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
-	testutil.CollectAndCount(collector)
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_success Whether the last fetch of the collector succeeded
+# TYPE rds_exporter_collector_success gauge
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="rds"} 1
+`, awsAccountID, awsRegion)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_success")
+	require.NoError(t, err, "should only fetch RDS instances")
 
 	counter := collector.GetStatistics()
 	assert.Equal(t, float64(0), counter.Errors, "should not have any error")
-	assert.Equal(t, float64(2), counter.RDSAPIcalls, "should have 2 call to RDS API (1 for clusters and another for instances)")
-	assert.Equal(t, float64(0), counter.EC2APIcalls, "should not have any call")
-	assert.Equal(t, float64(0), counter.ServiceQuotasAPICalls, "should not have any call")
-	assert.Equal(t, float64(0), counter.UsageAPIcalls, "should not have any call")
-	assert.Equal(t, float64(0), counter.CloudwatchAPICalls, "should not have any call")
 }
 
 func TestCollector(t *testing.T) {
@@ -74,18 +95,25 @@ func TestCollector(t *testing.T) {
 		CollectUsages:          true,
 	}
 
-	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil)
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
 
-	testutil.CollectAndCount(collector)
+	// Check fetched data sources
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_success Whether the last fetch of the collector succeeded
+# TYPE rds_exporter_collector_success gauge
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="cloudwatch"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="cloudwatch_clusters"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="ec2"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="rds"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="servicequotas"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="usage"} 1
+`, awsAccountID, awsRegion)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_success")
+	require.NoError(t, err, "should fetch all enabled data sources")
 
-	// Check API calls
 	counter := collector.GetStatistics()
 	assert.Equal(t, float64(0), counter.Errors, "should not have any error")
-	assert.Equal(t, float64(4), counter.RDSAPIcalls, "should have 4 call to RDS API")
-	assert.Equal(t, float64(1), counter.EC2APIcalls, "should have 1 call to EC2 API")
-	assert.Equal(t, float64(3), counter.ServiceQuotasAPICalls, "should have 1 call to ServiceQuota API")
-	assert.Equal(t, float64(1), counter.UsageAPIcalls, "should have 1 call to UsageAPIcalls API")
-	assert.Equal(t, float64(1), counter.CloudwatchAPICalls, "should have 1 call to CloudWatch API")
 
 	// Get internal metrics
 	metrics := collector.GetMetrics()
@@ -100,3 +128,1119 @@ func TestCollector(t *testing.T) {
 	assert.Equal(t, servicequotas_mock.ManualDBInstanceSnapshots, metrics.ServiceQuota.ManualDBInstanceSnapshots, "Manual instance snapshot quota should match")
 	assert.Equal(t, converter.GigaBytesToBytes(servicequotas_mock.TotalStorage), metrics.ServiceQuota.TotalStorage, "TotalStorage quota should match")
 }
+
+func TestCollectorWithCloudWatchMetricDefinitions(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	instance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*instance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("DiskQueueDepth"), Values: []float64{2}},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CloudWatchMetrics: []cloudwatch.MetricDefinition{
+			{CloudWatchName: "DiskQueueDepth", Name: "rds_disk_queue_depth_average", Help: "Number of outstanding I/Os waiting to access the disk"},
+		},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_disk_queue_depth_average Number of outstanding I/Os waiting to access the disk
+# TYPE rds_disk_queue_depth_average gauge
+rds_disk_queue_depth_average{aws_account_id="%s",aws_region="%s",dbidentifier="%s"} 2
+`, awsAccountID, awsRegion, *instance.DBInstanceIdentifier)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_disk_queue_depth_average")
+	require.NoError(t, err, "should expose configured CloudWatch metrics")
+
+	assert.Equal(t, 0, testutil.CollectAndCount(collector, "rds_cpu_usage_percent_average"), "should not collect default CloudWatch metrics when metrics are configured")
+}
+
+func TestCollectorWithCloudWatchStatistics(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	instance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*instance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{40}},
+		{Id: aws.String("m1_0"), Label: aws.String("CPUUtilization"), Values: []float64{100}},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CloudWatchMetrics: []cloudwatch.MetricDefinition{
+			{CloudWatchName: "CPUUtilization", Statistics: []string{"Average", "Maximum"}, Name: "rds_cpu_usage_percent", Help: "Instance CPU used"},
+		},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_cpu_usage_percent Instance CPU used
+# TYPE rds_cpu_usage_percent gauge
+rds_cpu_usage_percent{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",statistic="average"} 40
+rds_cpu_usage_percent{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",statistic="maximum"} 100
+`, awsAccountID, awsRegion, *instance.DBInstanceIdentifier)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_cpu_usage_percent")
+	require.NoError(t, err, "should expose each CloudWatch statistic with a statistic label")
+}
+
+func TestCollectorWithCloudWatchDatapointTimestamps(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	instance := rds_mock.NewRdsInstance()
+	timestamp := time.Date(2024, 1, 1, 12, 3, 0, 0, time.UTC)
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*instance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{15}, Timestamps: []time.Time{timestamp}},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CloudWatchTimestamps:   true,
+		CloudWatchMetrics: []cloudwatch.MetricDefinition{
+			{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average", Help: "Instance CPU used"},
+		},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_cpu_usage_percent_average Instance CPU used
+# TYPE rds_cpu_usage_percent_average gauge
+rds_cpu_usage_percent_average{aws_account_id="%s",aws_region="%s",dbidentifier="%s"} 15 %d
+`, awsAccountID, awsRegion, *instance.DBInstanceIdentifier, timestamp.UnixMilli())
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_cpu_usage_percent_average")
+	require.NoError(t, err, "should attach CloudWatch datapoint timestamps to metrics")
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	families, err := registry.Gather()
+	require.NoError(t, err, "Gather must succeed")
+
+	var age float64
+
+	for _, family := range families {
+		if family.GetName() == "rds_cloudwatch_datapoint_age_seconds" {
+			require.Len(t, family.GetMetric(), 1, "should expose one datapoint age per metric")
+			age = family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+
+	assert.GreaterOrEqual(t, age, time.Since(timestamp).Seconds()-60, "should expose the age of CloudWatch datapoints")
+}
+
+func TestCollectorWithIncompleteCloudWatchResults(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	instance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*instance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{15}, StatusCode: aws_cloudwatch_types.StatusCodeComplete},
+		{Id: aws.String("m1_0"), Label: aws.String("DatabaseConnections"), Values: []float64{}, StatusCode: aws_cloudwatch_types.StatusCodeInternalError},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		RefreshInterval:        time.Hour,
+		CloudWatchMetrics: []cloudwatch.MetricDefinition{
+			{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average", Help: "Instance CPU used"},
+			{CloudWatchName: "DatabaseConnections", Name: "rds_database_connections_average", Help: "The number of client network connections to the database instance"},
+		},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+	require.NoError(t, collector.Refresh(context.TODO()), "Refresh must succeed")
+
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_cloudwatch_incomplete_results_total Total number of CloudWatch queries without complete data (partial data, internal error or failed request)
+# TYPE rds_exporter_cloudwatch_incomplete_results_total counter
+rds_exporter_cloudwatch_incomplete_results_total{aws_account_id="%[1]s",aws_region="%[2]s"} 1
+# HELP rds_cpu_usage_percent_average Instance CPU used
+# TYPE rds_cpu_usage_percent_average gauge
+rds_cpu_usage_percent_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s"} 15
+`, awsAccountID, awsRegion, *instance.DBInstanceIdentifier)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_cloudwatch_incomplete_results_total", "rds_cpu_usage_percent_average", "rds_database_connections_average")
+	require.NoError(t, err, "should count incomplete CloudWatch results and keep complete ones")
+}
+
+func TestCollectorWithEngineSpecificCloudWatchMetrics(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	auroraInstance := rds_mock.NewRdsInstance()
+	auroraInstance.DBInstanceIdentifier = aws.String("aurora1")
+	auroraInstance.Engine = aws.String("aurora-postgresql")
+	postgresInstance := rds_mock.NewRdsInstance()
+	postgresInstance.DBInstanceIdentifier = aws.String("postgres1")
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*auroraInstance, *postgresInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("AuroraReplicaLag"), Values: []float64{20}},
+		{Id: aws.String("m0_1"), Label: aws.String("AuroraReplicaLag"), Values: []float64{40}},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CloudWatchMetrics: []cloudwatch.MetricDefinition{
+			{CloudWatchName: "AuroraReplicaLag", Engines: []string{"aurora"}, Scale: 0.001, Name: "rds_aurora_replica_lag_seconds", Help: "Amount of lag when replicating updates from the primary instance of the Aurora cluster"},
+		},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_aurora_replica_lag_seconds Amount of lag when replicating updates from the primary instance of the Aurora cluster
+# TYPE rds_aurora_replica_lag_seconds gauge
+rds_aurora_replica_lag_seconds{aws_account_id="%s",aws_region="%s",dbidentifier="aurora1"} 0.02
+`, awsAccountID, awsRegion)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_aurora_replica_lag_seconds")
+	require.NoError(t, err, "should expose engine-specific CloudWatch metrics of matching instances only")
+}
+
+func TestCollectorWithBurstableInstanceClasses(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	instance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*instance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceTypes: true,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_instance_burstable_ebs 1 if the instance class can burst over its EBS baseline bandwidth using EBS credits
+# TYPE rds_instance_burstable_ebs gauge
+rds_instance_burstable_ebs{aws_account_id="%[1]s",aws_region="%[2]s",instance_class="db.%[3]s"} 1
+# HELP rds_instance_burstable_performance 1 if the instance class has burstable CPU performance using CPU credits
+# TYPE rds_instance_burstable_performance gauge
+rds_instance_burstable_performance{aws_account_id="%[1]s",aws_region="%[2]s",instance_class="db.%[3]s"} 1
+`, awsAccountID, awsRegion, *instance.DBInstanceClass)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_instance_burstable_ebs", "rds_instance_burstable_performance")
+	require.NoError(t, err, "should expose whether instance classes are burstable")
+}
+
+func TestCollectorWithCloudWatchClusterMetrics(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	auroraCluster := rds_mock.NewAuroraCluster()
+	auroraCluster.Engine = aws.String("aurora-postgresql")
+	multiAZCluster := rds_mock.NewMultiAZCluster()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBClusters(*auroraCluster, *multiAZCluster)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("VolumeBytesUsed"), Values: []float64{1024}},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CollectClusterMetrics:  true,
+		CloudWatchClusterMetrics: []cloudwatch.MetricDefinition{
+			{CloudWatchName: "VolumeBytesUsed", Name: "rds_cluster_volume_used_bytes", Help: "Amount of storage used by the cluster volume"},
+		},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_cluster_volume_used_bytes Amount of storage used by the cluster volume
+# TYPE rds_cluster_volume_used_bytes gauge
+rds_cluster_volume_used_bytes{aws_account_id="%s",aws_region="%s",cluster_identifier="%s"} 1024
+`, awsAccountID, awsRegion, *auroraCluster.DBClusterIdentifier)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_cluster_volume_used_bytes")
+	require.NoError(t, err, "should expose CloudWatch metrics of Aurora clusters only")
+}
+
+func TestCollectorWithProxies(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	proxy := rds_mock.NewDBProxy()
+	availableTarget := rds_mock.NewDBProxyTarget("db-1", aws_rds_types.TargetStateAvailable)
+	unavailableTarget := rds_mock.NewDBProxyTarget("db-2", aws_rds_types.TargetStateUnavailable)
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBProxy(*proxy, *availableTarget, *unavailableTarget)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("ClientConnections"), Values: []float64{5}},
+		{Id: aws.String("m0_1"), Label: aws.String("DatabaseConnections"), Values: []float64{7}},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CollectProxies:         true,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_proxy_client_connections_average Number of client connections to the proxy
+# TYPE rds_proxy_client_connections_average gauge
+rds_proxy_client_connections_average{aws_account_id="%[1]s",aws_region="%[2]s",proxy="%[3]s"} 5
+# HELP rds_proxy_info RDS proxy information
+# TYPE rds_proxy_info gauge
+rds_proxy_info{arn="%[4]s",aws_account_id="%[1]s",aws_region="%[2]s",engine_family="POSTGRESQL",proxy="%[3]s",require_tls="true",status="available"} 1
+# HELP rds_proxy_target_database_connections_average Number of database connections from the proxy to the target
+# TYPE rds_proxy_target_database_connections_average gauge
+rds_proxy_target_database_connections_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="db-1",proxy="%[3]s",target_group="default"} 5
+rds_proxy_target_database_connections_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="db-2",proxy="%[3]s",target_group="default"} 7
+# HELP rds_proxy_target_health Proxy target health state (1 available | 0 registering) (-1 unavailable | -2 unknown)
+# TYPE rds_proxy_target_health gauge
+rds_proxy_target_health{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="db-1",proxy="%[3]s",reason="",role="READ_WRITE",target_group="default",type="RDS_INSTANCE"} 1
+rds_proxy_target_health{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="db-2",proxy="%[3]s",reason="CONNECTION_FAILED",role="READ_WRITE",target_group="default",type="RDS_INSTANCE"} -1
+`, awsAccountID, awsRegion, *proxy.DBProxyName, *proxy.DBProxyArn)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_proxy_client_connections_average", "rds_proxy_info", "rds_proxy_target_database_connections_average", "rds_proxy_target_health")
+	require.NoError(t, err, "should expose proxies, their targets health and CloudWatch metrics")
+}
+
+func TestCollectorWithPerformanceInsights(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+	rdsInstanceWithoutPerformanceInsights := rds_mock.NewRdsInstance()
+	rdsInstanceWithoutPerformanceInsights.PerformanceInsightsEnabled = aws.Bool(false)
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance, *rdsInstanceWithoutPerformanceInsights)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	var inputs []aws_pi.GetResourceMetricsInput
+
+	performanceInsightsClient := performanceinsights_mock.PerformanceInsightsClient{
+		Metrics: map[string][]aws_pi_types.MetricKeyDataPoints{
+			*rdsInstance.DbiResourceId: {
+				performanceinsights_mock.NewTotalLoad(2),
+				performanceinsights_mock.NewWaitEventLoad("IO", "IO:DataFileRead", 1.5),
+				performanceinsights_mock.NewSQLLoad("ABC123", "SELECT * FROM users WHERE id = ?", 1.25),
+			},
+		},
+		Inputs: &inputs,
+	}
+
+	configuration := exporter.Configuration{
+		CollectPerformanceInsights: true,
+		PerformanceInsightsTopN:    5,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, performanceInsightsClient, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_performance_insights_sql_load_average Average active sessions of the top tokenized SQL statements of the instance, from Performance Insights db.load.avg
+# TYPE rds_performance_insights_sql_load_average gauge
+rds_performance_insights_sql_load_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",sql_id="ABC123",statement="SELECT * FROM users WHERE id = ?"} 1.25
+# HELP rds_performance_insights_wait_event_load_average Average active sessions of the top wait events of the instance, from Performance Insights db.load.avg
+# TYPE rds_performance_insights_wait_event_load_average gauge
+rds_performance_insights_wait_event_load_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",wait_event="IO:DataFileRead",wait_event_type="IO"} 1.5
+`, awsAccountID, awsRegion, *rdsInstance.DBInstanceIdentifier)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_performance_insights_sql_load_average", "rds_performance_insights_wait_event_load_average")
+	require.NoError(t, err, "should expose Performance Insights top wait events and SQL statements")
+
+	require.Len(t, inputs, 1, "Only instances with Performance Insights enabled must be queried")
+	assert.Equal(t, *rdsInstance.DbiResourceId, aws.ToString(inputs[0].Identifier), "Instances must be queried by DbiResourceId")
+	assert.Equal(t, int32(5), aws.ToInt32(inputs[0].MetricQueries[0].GroupBy.Limit), "Top N mismatch")
+}
+
+func TestCollectorWithOSMetrics(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+	cloudWatchLogsClient := enhancedmonitoring_mock.CloudWatchLogsClient{
+		Documents: map[string]string{*rdsInstance.DbiResourceId: enhancedmonitoring_mock.NewDocument(*rdsInstance.DBInstanceIdentifier, time.Now())},
+	}
+
+	configuration := exporter.Configuration{
+		CollectOSMetrics: true,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, cloudWatchLogsClient)
+
+	expected := fmt.Sprintf(`
+# HELP rds_os_disk_await_seconds Average time to respond to requests of the device, including queue time
+# TYPE rds_os_disk_await_seconds gauge
+rds_os_disk_await_seconds{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",device="rdsdev"} 0.0025
+# HELP rds_os_filesystem_used_bytes Space used by files in the file system
+# TYPE rds_os_filesystem_used_bytes gauge
+rds_os_filesystem_used_bytes{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",mount_point="/rdsdbdata",name="rdsfilesys"} 1.048576e+06
+# HELP rds_os_load_average Number of processes requesting CPU time over the period
+# TYPE rds_os_load_average gauge
+rds_os_load_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",period="15m"} 0.1
+rds_os_load_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",period="1m"} 0.5
+rds_os_load_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",period="5m"} 0.25
+# HELP rds_os_process_memory_bytes Resident memory of processes with this name
+# TYPE rds_os_process_memory_bytes gauge
+rds_os_process_memory_bytes{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",process="OS processes"} 51200
+rds_os_process_memory_bytes{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",process="postgres"} 307200
+`, awsAccountID, awsRegion, *rdsInstance.DBInstanceIdentifier)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_os_disk_await_seconds", "rds_os_filesystem_used_bytes", "rds_os_load_average", "rds_os_process_memory_bytes")
+	require.NoError(t, err, "should expose Enhanced Monitoring OS metrics")
+}
+
+func TestCollectorWithAuroraOSMetrics(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+	cloudWatchLogsClient := enhancedmonitoring_mock.CloudWatchLogsClient{
+		Documents: map[string]string{*rdsInstance.DbiResourceId: enhancedmonitoring_mock.NewAuroraDocument(*rdsInstance.DBInstanceIdentifier, time.Now())},
+	}
+
+	configuration := exporter.Configuration{
+		CollectOSMetrics: true,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, cloudWatchLogsClient)
+
+	expected := fmt.Sprintf(`
+# HELP rds_os_disk_read_iops_average Number of read operations per second of the device
+# TYPE rds_os_disk_read_iops_average gauge
+rds_os_disk_read_iops_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",device="aurora-storage"} 4
+rds_os_disk_read_iops_average{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",device="rdsdev"} 1
+# HELP rds_os_disk_write_latency_seconds Average time between the submission and the completion of write operations of the device
+# TYPE rds_os_disk_write_latency_seconds gauge
+rds_os_disk_write_latency_seconds{aws_account_id="%[1]s",aws_region="%[2]s",dbidentifier="%[3]s",device="aurora-storage"} 0.0015
+`, awsAccountID, awsRegion, *rdsInstance.DBInstanceIdentifier)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_os_disk_read_iops_average", "rds_os_disk_write_latency_seconds")
+	require.NoError(t, err, "should expose Aurora storage and device I/O statistics")
+}
+
+func TestCollectorWithBackgroundRefresh(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectClusterMetrics: true,
+		RefreshInterval:       time.Hour,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	// Scrapes must not query AWS APIs when background refresh is enabled
+	err := testutil.CollectAndCompare(collector, strings.NewReader(upMetric(0)), "up")
+	require.NoError(t, err, "exporter should be down before first refresh")
+	assert.Equal(t, 0, rdsClient.GetDescribeDBInstancesCallCount(), "should not call RDS API before first refresh")
+
+	err = collector.Refresh(context.TODO())
+	require.NoError(t, err, "Refresh must succeed")
+
+	testutil.CollectAndCount(collector)
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(upMetric(1)), "up")
+	require.NoError(t, err, "exporter should be up after refresh")
+
+	assert.Equal(t, 1, rdsClient.GetDescribeDBInstancesCallCount(), "should only call RDS API during refresh")
+	assert.Len(t, collector.GetMetrics().RDS.Instances, 1, "should serve instances from last refresh")
+}
+
+// countingEC2Client counts calls to EC2 API
+type countingEC2Client struct {
+	ec2_mock.EC2Client
+	calls int
+}
+
+func (c *countingEC2Client) DescribeInstanceTypes(ctx context.Context, input *aws_ec2.DescribeInstanceTypesInput, optFns ...func(*aws_ec2.Options)) (*aws_ec2.DescribeInstanceTypesOutput, error) {
+	c.calls++
+
+	return c.EC2Client.DescribeInstanceTypes(ctx, input, optFns...)
+}
+
+// countingServiceQuotasClient counts calls to Service Quotas API
+type countingServiceQuotasClient struct {
+	servicequotas_mock.ServiceQuotasClient
+	calls int
+}
+
+func (c *countingServiceQuotasClient) ListServiceQuotas(ctx context.Context, input *aws_servicequotas.ListServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error) {
+	c.calls++
+
+	return c.ServiceQuotasClient.ListServiceQuotas(ctx, input, optFns...)
+}
+
+func (c *countingServiceQuotasClient) ListAWSDefaultServiceQuotas(ctx context.Context, input *aws_servicequotas.ListAWSDefaultServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListAWSDefaultServiceQuotasOutput, error) {
+	c.calls++
+
+	return c.ServiceQuotasClient.ListAWSDefaultServiceQuotas(ctx, input, optFns...)
+}
+
+func TestCollectorWithRefreshIntervals(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := &countingEC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := &countingServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceTypes:         true,
+		CollectQuotas:                true,
+		EC2RefreshInterval:           time.Hour,
+		ServiceQuotasRefreshInterval: time.Hour,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	for range 2 {
+		err := collector.Refresh(context.TODO())
+		require.NoError(t, err, "Refresh must succeed")
+	}
+
+	assert.Equal(t, 2, rdsClient.GetDescribeDBInstancesCallCount(), "should call RDS API on each refresh")
+	assert.Equal(t, 1, ec2Client.calls, "should not call EC2 API before end of its refresh interval")
+	assert.Equal(t, 2, servicequotasClient.calls, "should not call ServiceQuota API before end of its refresh interval")
+
+	count := testutil.CollectAndCount(collector, "rds_exporter_collector_last_success_timestamp_seconds")
+	assert.Equal(t, 3, count, "should expose last success of rds, ec2 and servicequotas collectors")
+}
+
+func TestCollectorWithQuotaUtilization(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient()
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{
+		Metrics: []aws_cloudwatch_types.MetricDataResult{
+			{
+				Label:  aws.String("AllocatedStorage"),
+				Values: []float64{servicequotas_mock.TotalStorage / 2}, // Gigabytes
+			},
+			{
+				Label:  aws.String("DBClusters"),
+				Values: []float64{servicequotas_mock.UnknownServiceQuota / 2},
+			},
+		},
+	}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectQuotas: true,
+		CollectUsages: true,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_quota_utilization_ratio Ratio of the RDS service quota used by AWS/Usage resource
+# TYPE rds_quota_utilization_ratio gauge
+rds_quota_utilization_ratio{aws_account_id="%[1]s",aws_region="%[2]s",resource="AllocatedStorage"} 0.5
+rds_quota_utilization_ratio{aws_account_id="%[1]s",aws_region="%[2]s",resource="DBClusters"} 0.5
+# HELP rds_usage_resource_average Number of RDS resources in the AWS account by AWS/Usage resource
+# TYPE rds_usage_resource_average gauge
+rds_usage_resource_average{aws_account_id="%[1]s",aws_region="%[2]s",resource="DBClusters"} 21
+`, awsAccountID, awsRegion)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_quota_utilization_ratio", "rds_usage_resource_average")
+	require.NoError(t, err, "should expose quota utilization of resources with usage and quota")
+
+	assert.Equal(t, len(servicequotas.UsageQuotas)-1, testutil.CollectAndCount(collector, "rds_quota_resource_average"), "should expose quota of resources except allocated storage")
+}
+
+func TestCollectorWithQuotaValues(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient()
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectQuotas: true,
+		QuotaCodes:    []string{servicequotas.DBinstancesQuotacode},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_quota_value Value of the AWS RDS service quota, applied to the AWS account or AWS default
+# TYPE rds_quota_value gauge
+rds_quota_value{adjustable="true",aws_account_id="%[1]s",aws_region="%[2]s",quota_code="%[3]s",quota_name="Quota %[3]s",type="applied"} %[4]v
+rds_quota_value{adjustable="true",aws_account_id="%[1]s",aws_region="%[2]s",quota_code="%[3]s",quota_name="Quota %[3]s",type="default"} %[5]v
+`, awsAccountID, awsRegion, servicequotas.DBinstancesQuotacode, servicequotas_mock.DBinstancesQuota, servicequotas_mock.DefaultQuota)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_quota_value")
+	require.NoError(t, err, "should only expose applied and default values of configured quotas")
+
+	assert.Equal(t, len(servicequotas.UsageQuotas)-1, testutil.CollectAndCount(collector, "rds_quota_resource_average"), "should not filter quotas of AWS/Usage resources")
+}
+
+func TestCollectorSuccessMetrics(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClientListError{}
+
+	configuration := exporter.Configuration{
+		CollectQuotas: true,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_success Whether the last fetch of the collector succeeded
+# TYPE rds_exporter_collector_success gauge
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="rds"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="servicequotas"} 0
+`, awsAccountID, awsRegion)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_success")
+	require.NoError(t, err, "should expose success of each collector")
+
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "rds_exporter_collector_duration_seconds"), "should expose duration of each collector")
+}
+
+func TestCollectorScrapeTimeout(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClientTimeout{}
+
+	configuration := exporter.Configuration{
+		CollectQuotas: true,
+		ScrapeTimeout: 100 * time.Millisecond,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	start := time.Now()
+	err := collector.Refresh(context.TODO())
+	require.NoError(t, err, "Refresh must succeed when only quotas time out")
+	assert.Less(t, time.Since(start), 5*time.Second, "should cancel outstanding AWS API calls after scrape timeout")
+
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_success Whether the last fetch of the collector succeeded
+# TYPE rds_exporter_collector_success gauge
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="rds"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="servicequotas"} 0
+`, awsAccountID, awsRegion)
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_success")
+	require.NoError(t, err, "should mark timed out collectors as failed")
+
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "rds_instance_info"), "should expose metrics collected before the timeout")
+}
+
+func TestCollectorExposesPartialMetricsOnRDSTimeout(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{Metrics: []aws_cloudwatch_types.MetricDataResult{
+		{Id: aws.String("m0_0"), Label: aws.String("CPUUtilization"), Values: []float64{40}},
+	}}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CollectInstanceTypes:   true,
+		CloudWatchMetrics: []cloudwatch.MetricDefinition{
+			{CloudWatchName: "CPUUtilization", Name: "rds_cpu_usage_percent_average", Help: "Instance CPU used"},
+		},
+		RefreshInterval: time.Hour, // Refreshes are triggered by the test
+		ScrapeTimeout:   100 * time.Millisecond,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	err := collector.Refresh(context.TODO())
+	require.NoError(t, err, "First refresh must succeed")
+
+	rdsClient.DescribeDBInstancesTimeout = true
+
+	err = collector.Refresh(context.TODO())
+	require.Error(t, err, "Refresh must fail when DescribeDBInstances times out")
+
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_success Whether the last fetch of the collector succeeded
+# TYPE rds_exporter_collector_success gauge
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="cloudwatch"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="ec2"} 1
+rds_exporter_collector_success{aws_account_id="%[1]s",aws_region="%[2]s",collector="rds"} 0
+`, awsAccountID, awsRegion)
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_success")
+	require.NoError(t, err, "should mark the timed out RDS collector as failed")
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(upMetric(0)), "up")
+	require.NoError(t, err, "exporter should be down when the RDS collector fails")
+
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "rds_instance_info"), "should expose RDS metrics collected before the timeout")
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "rds_instance_vcpu_average"), "should expose EC2 metrics collected before the timeout")
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "rds_cpu_usage_percent_average"), "should expose CloudWatch metrics collected before the timeout")
+}
+
+// exhaustedBudget reports no available budget for listed AWS services
+type exhaustedBudget struct {
+	services []string
+}
+
+func (b exhaustedBudget) Available(awsAccountID string, awsRegion string, service string, calls int) bool {
+	for _, exhausted := range b.services {
+		if exhausted == service {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestCollectorSkipsCollectorsWithoutBudget(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectQuotas: true,
+		APIBudget:     exhaustedBudget{services: []string{"servicequotas"}},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_skipped_total Total number of refreshes where the collector was skipped because the AWS API budget was exhausted
+# TYPE rds_exporter_collector_skipped_total counter
+rds_exporter_collector_skipped_total{aws_account_id="%[1]s",aws_region="%[2]s",collector="servicequotas"} 1
+`, awsAccountID, awsRegion)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_skipped_total")
+	require.NoError(t, err, "should count skipped collectors")
+
+	assert.Zero(t, collector.GetMetrics().ServiceQuota.DBinstances, "should not fetch quotas without budget")
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "rds_instance_info"), "should still collect high priority metrics")
+}
+
+// limitedBudget allows at most available calls to each AWS service
+type limitedBudget struct {
+	available int
+}
+
+func (b limitedBudget) Available(awsAccountID string, awsRegion string, service string, calls int) bool {
+	return calls <= b.available
+}
+
+func TestCollectorChecksLogsSizeBudgetOnFirstRefresh(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+
+	configuration := exporter.Configuration{
+		CollectLogsSize: true,
+		APIBudget:       limitedBudget{available: 0},
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2_mock.EC2Client{}, cloudwatch_mock.CloudwatchClient{}, servicequotas_mock.ServiceQuotasClient{}, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_skipped_total Total number of refreshes where the collector was skipped because the AWS API budget was exhausted
+# TYPE rds_exporter_collector_skipped_total counter
+rds_exporter_collector_skipped_total{aws_account_id="%[1]s",aws_region="%[2]s",collector="logs_size"} 1
+`, awsAccountID, awsRegion)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_skipped_total")
+	require.NoError(t, err, "should estimate logs size calls from described instances")
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "rds_instance_info"), "should still collect instances")
+}
+
+func TestCollectorEstimatesQuotasBudgetFromLastFetch(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	logger, _ := logger.New(true, "text")
+	rdsClient := rds_mock.NewRDSClient()
+
+	// Each quota is listed in its own page, so a fetch requires more calls than the first estimate
+	configuration := exporter.Configuration{
+		CollectQuotas:   true,
+		APIBudget:       limitedBudget{available: 10},
+		RefreshInterval: time.Hour,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2_mock.EC2Client{}, cloudwatch_mock.CloudwatchClient{}, servicequotas_mock.ServiceQuotasClientPaginated{}, nil, nil, nil)
+
+	expected := fmt.Sprintf(`
+# HELP rds_exporter_collector_skipped_total Total number of refreshes where the collector was skipped because the AWS API budget was exhausted
+# TYPE rds_exporter_collector_skipped_total counter
+rds_exporter_collector_skipped_total{aws_account_id="%[1]s",aws_region="%[2]s",collector="servicequotas"} 1
+`, awsAccountID, awsRegion)
+
+	require.NoError(t, collector.Refresh(context.TODO()), "first fetch should be allowed by the default estimate")
+	assert.Equal(t, servicequotas_mock.DBinstancesQuota, collector.GetMetrics().ServiceQuota.DBinstances, "should fetch quotas")
+
+	require.NoError(t, collector.Refresh(context.TODO()))
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_exporter_collector_skipped_total")
+	require.NoError(t, err, "should estimate quotas calls from pages of the last fetch")
+}
+
+func TestMultiCollectorIsolatesRegionFailures(t *testing.T) {
+	awsAccountID := "123456789012"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+	failingRDSClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	failingRDSClient.Error = errors.New("AWS API is unavailable")
+
+	healthyTarget := exporter.Target{
+		AWSAccountID:        awsAccountID,
+		AWSRegion:           "eu-west-3",
+		RDSClient:           rds_mock.NewRDSClient().WithDBInstances(*rdsInstance),
+		EC2Client:           ec2_mock.EC2Client{},
+		CloudWatchClient:    cloudwatch_mock.CloudwatchClient{},
+		ServiceQuotasClient: servicequotas_mock.ServiceQuotasClient{},
+	}
+	failingTarget := healthyTarget
+	failingTarget.AWSRegion = "us-east-1"
+	failingTarget.RDSClient = failingRDSClient
+
+	configuration := exporter.Configuration{
+		CollectMaintenances: true,
+	}
+
+	collector := exporter.NewMultiCollector(context.TODO(), *logger, configuration, healthyTarget, failingTarget)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(upMetric(1)), "up")
+	require.NoError(t, err, "exporter should stay up when a region fails")
+
+	expectedTargetUp := fmt.Sprintf(`
+# HELP rds_exporter_target_up Was the last refresh of the AWS account and region successful
+# TYPE rds_exporter_target_up gauge
+rds_exporter_target_up{aws_account_id="%[1]s",aws_region="eu-west-3"} 1
+rds_exporter_target_up{aws_account_id="%[1]s",aws_region="us-east-1"} 0
+`, awsAccountID)
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expectedTargetUp), "rds_exporter_target_up")
+	require.NoError(t, err, "failing region should be reported as down")
+
+	expected := fmt.Sprintf(`
+# HELP rds_backup_retention_period_seconds Automatic DB snapshots retention period
+# TYPE rds_backup_retention_period_seconds gauge
+rds_backup_retention_period_seconds{aws_account_id="%s",aws_region="eu-west-3",dbidentifier="%s"} %d
+`, awsAccountID, *rdsInstance.DBInstanceIdentifier, converter.DaystoSeconds(*rdsInstance.BackupRetentionPeriod))
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_backup_retention_period_seconds")
+	require.NoError(t, err, "metrics of healthy regions should be exposed")
+}
+
+func TestMultiCollectorExposesUnavailableTargets(t *testing.T) {
+	awsAccountID := "123456789012"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+
+	healthyTarget := exporter.Target{
+		AWSAccountID:        awsAccountID,
+		AWSRegion:           "eu-west-3",
+		RDSClient:           rds_mock.NewRDSClient().WithDBInstances(*rdsInstance),
+		EC2Client:           ec2_mock.EC2Client{},
+		CloudWatchClient:    cloudwatch_mock.CloudwatchClient{},
+		ServiceQuotasClient: servicequotas_mock.ServiceQuotasClient{},
+	}
+	unavailableTarget := exporter.Target{
+		AWSAccountID: "210987654321",
+		AWSRegion:    "eu-west-3",
+		Err:          errors.New("assume role is denied"),
+	}
+
+	collector := exporter.NewMultiCollector(context.TODO(), *logger, exporter.Configuration{}, healthyTarget, unavailableTarget)
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(upMetric(1)), "up")
+	require.NoError(t, err, "exporter should stay up when a target can't be initialized")
+
+	expectedTargetUp := fmt.Sprintf(`
+# HELP rds_exporter_target_up Was the last refresh of the AWS account and region successful
+# TYPE rds_exporter_target_up gauge
+rds_exporter_target_up{aws_account_id="%s",aws_region="eu-west-3"} 1
+rds_exporter_target_up{aws_account_id="210987654321",aws_region="eu-west-3"} 0
+`, awsAccountID)
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(expectedTargetUp), "rds_exporter_target_up")
+	require.NoError(t, err, "unavailable target should be reported as down")
+
+	collector = exporter.NewMultiCollector(context.TODO(), *logger, exporter.Configuration{}, unavailableTarget)
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(upMetric(0)), "up")
+	require.NoError(t, err, "exporter should be down when no target can be collected")
+}
+
+func TestMultiCollectorWithSeveralAccounts(t *testing.T) {
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+
+	var targets []exporter.Target
+
+	for _, awsAccountID := range []string{"111111111111", "222222222222"} {
+		targets = append(targets, exporter.Target{
+			AWSAccountID:        awsAccountID,
+			AWSRegion:           awsRegion,
+			RDSClient:           rds_mock.NewRDSClient().WithDBInstances(*rdsInstance),
+			EC2Client:           ec2_mock.EC2Client{},
+			CloudWatchClient:    cloudwatch_mock.CloudwatchClient{},
+			ServiceQuotasClient: servicequotas_mock.ServiceQuotasClient{},
+		})
+	}
+
+	collector := exporter.NewMultiCollector(context.TODO(), *logger, exporter.Configuration{}, targets...)
+
+	expected := fmt.Sprintf(`
+# HELP rds_backup_retention_period_seconds Automatic DB snapshots retention period
+# TYPE rds_backup_retention_period_seconds gauge
+rds_backup_retention_period_seconds{aws_account_id="111111111111",aws_region="%[1]s",dbidentifier="%[2]s"} %[3]d
+rds_backup_retention_period_seconds{aws_account_id="222222222222",aws_region="%[1]s",dbidentifier="%[2]s"} %[3]d
+`, awsRegion, *rdsInstance.DBInstanceIdentifier, converter.DaystoSeconds(*rdsInstance.BackupRetentionPeriod))
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "rds_backup_retention_period_seconds")
+	require.NoError(t, err, "instances of each account should be exposed with their account ID")
+
+	err = testutil.CollectAndCompare(collector, strings.NewReader(upMetric(1)), "up")
+	require.NoError(t, err, "exporter should be up when all accounts succeed")
+}
+
+func TestMultiCollectorSetTargets(t *testing.T) {
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(true, "text")
+
+	newTarget := func(awsAccountID string) exporter.Target {
+		return exporter.Target{
+			AWSAccountID:        awsAccountID,
+			AWSRegion:           awsRegion,
+			RDSClient:           rds_mock.NewRDSClient().WithDBInstances(*rdsInstance),
+			EC2Client:           ec2_mock.EC2Client{},
+			CloudWatchClient:    cloudwatch_mock.CloudwatchClient{},
+			ServiceQuotasClient: servicequotas_mock.ServiceQuotasClient{},
+		}
+	}
+
+	knownRDSClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	knownTarget := newTarget("111111111111")
+	knownTarget.RDSClient = knownRDSClient
+
+	collector := exporter.NewMultiCollector(context.TODO(), *logger, exporter.Configuration{}, knownTarget)
+
+	err := collector.Refresh(context.TODO())
+	require.NoError(t, err, "Refresh must succeed")
+
+	collector.SetTargets(newTarget("111111111111"), newTarget("222222222222"), newTarget("222222222222"))
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "rds_instance_info"), "should collect new targets and ignore duplicated targets")
+	assert.Equal(t, 2, knownRDSClient.GetDescribeDBInstancesCallCount(), "should keep collector of already known target")
+
+	collector.SetTargets(newTarget("222222222222"))
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "rds_instance_info"), "should stop collecting removed targets")
+}
+
+func TestCollectorConcurrentScrapes(t *testing.T) {
+	awsAccountID := "123456789012"
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(false, "text")
+	rdsClient := rds_mock.NewRDSClient().WithDBInstances(*rdsInstance)
+	ec2Client := ec2_mock.EC2Client{}
+	cloudWatchClient := cloudwatch_mock.CloudwatchClient{}
+	servicequotasClient := servicequotas_mock.ServiceQuotasClient{}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CollectInstanceTypes:   true,
+		CollectUsages:          true,
+		CollectQuotas:          true,
+		CollectClusterMetrics:  true,
+	}
+
+	collector := exporter.NewCollector(*logger, configuration, awsAccountID, awsRegion, rdsClient, ec2Client, cloudWatchClient, servicequotasClient, nil, nil, nil)
+
+	scrapes := 10
+
+	var wg sync.WaitGroup
+
+	for range scrapes {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			testutil.CollectAndCount(collector)
+		}()
+
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, collector.Refresh(context.TODO()), "Refresh must succeed")
+		}()
+	}
+
+	wg.Wait()
+
+	counter := collector.GetStatistics()
+	assert.Equal(t, float64(0), counter.Errors, "should not have errors")
+	assert.Positive(t, rdsClient.GetDescribeDBInstancesCallCount(), "should call RDS API")
+	assert.LessOrEqual(t, rdsClient.GetDescribeDBInstancesCallCount(), 2*scrapes, "should call RDS API at most once per refresh and scrape")
+}
+
+func TestMultiCollectorConcurrentScrapes(t *testing.T) {
+	awsRegion := "eu-west-3"
+
+	rdsInstance := rds_mock.NewRdsInstance()
+
+	logger, _ := logger.New(false, "text")
+
+	newTarget := func(awsAccountID string) exporter.Target {
+		return exporter.Target{
+			AWSAccountID:        awsAccountID,
+			AWSRegion:           awsRegion,
+			RDSClient:           rds_mock.NewRDSClient().WithDBInstances(*rdsInstance),
+			EC2Client:           ec2_mock.EC2Client{},
+			CloudWatchClient:    cloudwatch_mock.CloudwatchClient{},
+			ServiceQuotasClient: servicequotas_mock.ServiceQuotasClient{},
+		}
+	}
+
+	configuration := exporter.Configuration{
+		CollectInstanceMetrics: true,
+		CollectInstanceTypes:   true,
+		CollectQuotas:          true,
+	}
+
+	collector := exporter.NewMultiCollector(context.TODO(), *logger, configuration, newTarget("111111111111"))
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			testutil.CollectAndCount(collector)
+		}()
+
+		go func() {
+			defer wg.Done()
+			collector.SetTargets(newTarget("111111111111"), newTarget("222222222222"))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 2, testutil.CollectAndCount(collector, "rds_instance_info"), "should collect all targets")
+}
+
+// upMetric returns the expected exposition of the up metric
+func upMetric(value int) string {
+	return fmt.Sprintf(`# HELP up Was the last scrape of RDS successful
+# TYPE up counter
+up %d
+`, value)
+}
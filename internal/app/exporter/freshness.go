@@ -0,0 +1,107 @@
+package exporter
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// Collector names used to track freshness of each data source
+const (
+	collectorRDS                 = "rds"
+	collectorCloudWatch          = "cloudwatch"
+	collectorCloudWatchClusters  = "cloudwatch_clusters"
+	collectorCloudWatchProxies   = "cloudwatch_proxies"
+	collectorUsage               = "usage"
+	collectorEC2                 = "ec2"
+	collectorServiceQuotas       = "servicequotas"
+	collectorEngineSupport       = "engine_support"
+	collectorLogsSize            = "logs_size"
+	collectorProxies             = "proxies"
+	collectorPerformanceInsights = "performance_insights"
+	collectorOSMetrics           = "os_metrics"
+)
+
+// collectorResult is the result of the last fetch of a collector
+type collectorResult struct {
+	success  bool
+	duration time.Duration
+}
+
+// freshness tracks the last fetch and the last successful fetch of each collector
+type freshness struct {
+	mutex       sync.Mutex
+	lastSuccess map[string]time.Time
+	lastResult  map[string]collectorResult
+	skipped     map[string]float64
+}
+
+func newFreshness() *freshness {
+	return &freshness{
+		lastSuccess: make(map[string]time.Time),
+		lastResult:  make(map[string]collectorResult),
+		skipped:     make(map[string]float64),
+	}
+}
+
+// isStale returns true if the collector never succeeded or if its last success is older than the refresh interval
+func (f *freshness) isStale(collector string, interval time.Duration, now time.Time) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	lastSuccess, found := f.lastSuccess[collector]
+	if !found {
+		return true
+	}
+
+	return now.Sub(lastSuccess) >= interval
+}
+
+// markResult records the result of a fetch of the collector started at start
+func (f *freshness) markResult(collector string, success bool, start time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := time.Now()
+
+	f.lastResult[collector] = collectorResult{
+		success:  success,
+		duration: now.Sub(start),
+	}
+
+	if success {
+		f.lastSuccess[collector] = now
+	}
+}
+
+// markSkipped records that the collector was skipped
+func (f *freshness) markSkipped(collector string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.skipped[collector]++
+}
+
+// skippedCounts returns a copy of the number of times each collector was skipped
+func (f *freshness) skippedCounts() map[string]float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return maps.Clone(f.skipped)
+}
+
+// lastSuccesses returns a copy of the last successful fetch time of each collector
+func (f *freshness) lastSuccesses() map[string]time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return maps.Clone(f.lastSuccess)
+}
+
+// lastResults returns a copy of the last fetch result of each collector
+func (f *freshness) lastResults() map[string]collectorResult {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return maps.Clone(f.lastResult)
+}
@@ -16,6 +16,9 @@ type rdsClient interface {
 	DescribeDBLogFiles(context.Context, *aws_rds.DescribeDBLogFilesInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBLogFilesOutput, error)
 	DescribeDBEngineVersions(context.Context, *aws_rds.DescribeDBEngineVersionsInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBEngineVersionsOutput, error)
 	DescribeDBMajorEngineVersions(context.Context, *aws_rds.DescribeDBMajorEngineVersionsInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBMajorEngineVersionsOutput, error)
+	DescribeDBProxies(context.Context, *aws_rds.DescribeDBProxiesInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxiesOutput, error)
+	DescribeDBProxyTargetGroups(context.Context, *aws_rds.DescribeDBProxyTargetGroupsInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxyTargetGroupsOutput, error)
+	DescribeDBProxyTargets(context.Context, *aws_rds.DescribeDBProxyTargetsInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxyTargetsOutput, error)
 }
 
 type EC2Client interface {
@@ -27,5 +30,6 @@ type cloudWatchClient interface {
 }
 
 type servicequotasClient interface {
-	GetServiceQuota(context.Context, *aws_servicequotas.GetServiceQuotaInput, ...func(*aws_servicequotas.Options)) (*aws_servicequotas.GetServiceQuotaOutput, error)
+	ListServiceQuotas(context.Context, *aws_servicequotas.ListServiceQuotasInput, ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error)
+	ListAWSDefaultServiceQuotas(context.Context, *aws_servicequotas.ListAWSDefaultServiceQuotasInput, ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListAWSDefaultServiceQuotasOutput, error)
 }
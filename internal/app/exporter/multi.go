@@ -0,0 +1,214 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/enhancedmonitoring"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/performanceinsights"
+)
+
+// Target is an AWS account and region to collect
+type Target struct {
+	AWSAccountID              string
+	AWSRegion                 string
+	RDSClient                 rdsClient
+	EC2Client                 EC2Client
+	CloudWatchClient          cloudWatchClient
+	ServiceQuotasClient       servicequotasClient
+	TagClient                 resourcegroupstaggingapi.GetResourcesAPIClient
+	PerformanceInsightsClient performanceinsights.Client
+	CloudWatchLogsClient      enhancedmonitoring.Client
+
+	// Err is the initialization error of the target (eg. denied assume role)
+	// Targets with an error are not collected and are exposed as down by rds_exporter_target_up
+	Err error
+}
+
+// multiCollector aggregates collectors of several AWS targets into a single Prometheus collector
+// Each collector fetches its own target, so a target failure does not prevent other targets to be exposed
+type multiCollector struct {
+	logger        slog.Logger
+	configuration Configuration
+	descriptors   *rdsCollector // exposes descriptors and exporter metrics shared by all collectors
+	coalescer     coalescer     // shares in-flight refresh of all targets with concurrent scrapes
+
+	mutex              sync.RWMutex // protects collectors and unavailableTargets
+	collectors         []*rdsCollector
+	unavailableTargets []Target
+}
+
+// NewMultiCollector returns a collector of the targets, AWS API calls in progress are cancelled when ctx is done
+func NewMultiCollector(ctx context.Context, logger slog.Logger, collectorConfiguration Configuration, targets ...Target) *multiCollector {
+	m := &multiCollector{
+		logger:        logger,
+		configuration: collectorConfiguration,
+		descriptors:   NewCollector(logger, collectorConfiguration, "", "", nil, nil, nil, nil, nil, nil, nil),
+		coalescer:     coalescer{ctx: ctx, timeout: collectorConfiguration.ScrapeTimeout},
+	}
+
+	m.SetTargets(targets...)
+
+	return m
+}
+
+func targetKey(awsAccountID string, awsRegion string) string {
+	return awsAccountID + "/" + awsRegion
+}
+
+// SetTargets replaces collected targets
+// Collectors of already known targets are kept to preserve their cache and refresh intervals
+func (m *multiCollector) SetTargets(targets ...Target) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	existingCollectors := make(map[string]*rdsCollector, len(m.collectors))
+	for _, collector := range m.collectors {
+		existingCollectors[targetKey(collector.awsAccountID, collector.awsRegion)] = collector
+	}
+
+	collectors := make([]*rdsCollector, 0, len(targets))
+	seen := make(map[string]bool, len(targets))
+
+	var unavailableTargets []Target
+
+	for _, target := range targets {
+		key := targetKey(target.AWSAccountID, target.AWSRegion)
+		if seen[key] {
+			m.logger.Warn("ignore duplicated target", "aws_account_id", target.AWSAccountID, "aws_region", target.AWSRegion)
+
+			continue
+		}
+
+		seen[key] = true
+
+		if target.Err != nil {
+			m.logger.Warn("ignore unavailable target", "aws_account_id", target.AWSAccountID, "aws_region", target.AWSRegion, "reason", target.Err)
+
+			unavailableTargets = append(unavailableTargets, target)
+
+			continue
+		}
+
+		collector, found := existingCollectors[key]
+		if !found {
+			m.logger.Info("start collecting target", "aws_account_id", target.AWSAccountID, "aws_region", target.AWSRegion)
+
+			targetLogger := *m.logger.With("aws_account_id", target.AWSAccountID, "aws_region", target.AWSRegion)
+			collector = NewCollector(targetLogger, m.configuration, target.AWSAccountID, target.AWSRegion, target.RDSClient, target.EC2Client, target.CloudWatchClient, target.ServiceQuotasClient, target.TagClient, target.PerformanceInsightsClient, target.CloudWatchLogsClient)
+		}
+
+		delete(existingCollectors, key)
+
+		collectors = append(collectors, collector)
+	}
+
+	for _, collector := range existingCollectors {
+		m.logger.Info("stop collecting target", "aws_account_id", collector.awsAccountID, "aws_region", collector.awsRegion)
+	}
+
+	m.collectors = collectors
+	m.unavailableTargets = unavailableTargets
+}
+
+func (m *multiCollector) getCollectors() []*rdsCollector {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.collectors
+}
+
+func (m *multiCollector) getUnavailableTargets() []Target {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.unavailableTargets
+}
+
+func (m *multiCollector) Describe(ch chan<- *prometheus.Desc) {
+	// All collectors share the same descriptors
+	m.descriptors.Describe(ch)
+}
+
+// Refresh queries AWS APIs of all targets concurrently
+// Concurrent calls wait for and share the result of the in-flight refresh
+func (m *multiCollector) Refresh(ctx context.Context) error {
+	return m.coalescer.do(ctx, m.refresh)
+}
+
+func (m *multiCollector) refresh(ctx context.Context) error {
+	collectors := m.getCollectors()
+
+	var wg sync.WaitGroup
+
+	errs := make([]error, len(collectors))
+
+	for i, collector := range collectors {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs[i] = collector.Refresh(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Run refreshes metrics of all targets in background every RefreshInterval until the context is cancelled
+func (m *multiCollector) Run(ctx context.Context) {
+	refreshPeriodically(ctx, m.configuration.RefreshInterval, m.Refresh)
+}
+
+func (m *multiCollector) Collect(ch chan<- prometheus.Metric) {
+	// Query AWS APIs on each scrape when background refresh is disabled
+	if m.configuration.RefreshInterval <= 0 {
+		refreshOnScrape(m.configuration.ScrapeTimeout, func(ctx context.Context) error {
+			return m.coalescer.doScrape(ctx, m.refresh)
+		})
+	}
+
+	collectors := m.getCollectors()
+	unavailableTargets := m.getUnavailableTargets()
+	snapshots := make([]snapshot, len(collectors))
+
+	var errorsCount float64
+
+	// Exporter is up while at least one target can be collected, each target health is exposed by rds_exporter_target_up
+	healthy := len(collectors) == 0 && len(unavailableTargets) == 0
+
+	for i, collector := range collectors {
+		snapshots[i] = collector.getSnapshot()
+		errorsCount += snapshots[i].counters.Errors
+		healthy = healthy || snapshots[i].healthy()
+	}
+
+	// Exporter metrics are exposed once for all targets
+	m.descriptors.collectExporterMetrics(ch, errorsCount, m.coalescer.coalescedCount(), healthy)
+
+	for i, collector := range collectors {
+		collector.collectSnapshot(ch, snapshots[i])
+	}
+
+	for _, target := range unavailableTargets {
+		ch <- prometheus.MustNewConstMetric(m.descriptors.targetUp, prometheus.GaugeValue, exporterDownStatusCode, target.AWSAccountID, target.AWSRegion)
+	}
+}
+
+// GetStatistics returns the sum of counters of all targets
+func (m *multiCollector) GetStatistics() counters {
+	var total counters
+
+	for _, collector := range m.getCollectors() {
+		total = total.add(collector.GetStatistics())
+	}
+
+	return total
+}
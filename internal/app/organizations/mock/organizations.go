@@ -0,0 +1,90 @@
+// Package mocks contains mock for organizations client
+package mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_organizations "github.com/aws/aws-sdk-go-v2/service/organizations"
+	aws_organizations_types "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+type OrganizationsClient struct {
+	Accounts            []aws_organizations_types.Account
+	Parents             map[string]string            // account or organizational unit ID => parent ID
+	OrganizationalUnits []string                     // organizational units IDs
+	Tags                map[string]map[string]string // account ID => tags
+}
+
+func NewOrganizationsClient() *OrganizationsClient {
+	return &OrganizationsClient{
+		Parents: make(map[string]string),
+		Tags:    make(map[string]map[string]string),
+	}
+}
+
+// WithAccount adds an account in the parent organizational unit
+func (m *OrganizationsClient) WithAccount(account aws_organizations_types.Account, parent string, tags map[string]string) *OrganizationsClient {
+	m.Accounts = append(m.Accounts, account)
+	m.Parents[aws.ToString(account.Id)] = parent
+	m.Tags[aws.ToString(account.Id)] = tags
+
+	return m
+}
+
+// WithOrganizationalUnit adds an organizational unit in the parent organizational unit
+func (m *OrganizationsClient) WithOrganizationalUnit(organizationalUnit string, parent string) *OrganizationsClient {
+	m.OrganizationalUnits = append(m.OrganizationalUnits, organizationalUnit)
+	m.Parents[organizationalUnit] = parent
+
+	return m
+}
+
+func (m *OrganizationsClient) ListAccounts(ctx context.Context, input *aws_organizations.ListAccountsInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListAccountsOutput, error) {
+	return &aws_organizations.ListAccountsOutput{Accounts: m.Accounts}, nil
+}
+
+func (m *OrganizationsClient) ListAccountsForParent(ctx context.Context, input *aws_organizations.ListAccountsForParentInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListAccountsForParentOutput, error) {
+	var accounts []aws_organizations_types.Account
+
+	for _, account := range m.Accounts {
+		if m.Parents[aws.ToString(account.Id)] == aws.ToString(input.ParentId) {
+			accounts = append(accounts, account)
+		}
+	}
+
+	return &aws_organizations.ListAccountsForParentOutput{Accounts: accounts}, nil
+}
+
+func (m *OrganizationsClient) ListOrganizationalUnitsForParent(ctx context.Context, input *aws_organizations.ListOrganizationalUnitsForParentInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListOrganizationalUnitsForParentOutput, error) {
+	var organizationalUnits []aws_organizations_types.OrganizationalUnit
+
+	for _, organizationalUnit := range m.OrganizationalUnits {
+		if m.Parents[organizationalUnit] == aws.ToString(input.ParentId) {
+			organizationalUnits = append(organizationalUnits, aws_organizations_types.OrganizationalUnit{Id: aws.String(organizationalUnit)})
+		}
+	}
+
+	return &aws_organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: organizationalUnits}, nil
+}
+
+func (m *OrganizationsClient) ListTagsForResource(ctx context.Context, input *aws_organizations.ListTagsForResourceInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListTagsForResourceOutput, error) {
+	var tags []aws_organizations_types.Tag
+
+	for key, value := range m.Tags[aws.ToString(input.ResourceId)] {
+		tags = append(tags, aws_organizations_types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	return &aws_organizations.ListTagsForResourceOutput{Tags: tags}, nil
+}
+
+// NewAccount returns an organization account
+func NewAccount(accountID string, status aws_organizations_types.AccountStatus) aws_organizations_types.Account {
+	return aws_organizations_types.Account{
+		Id:     aws.String(accountID),
+		Arn:    aws.String(fmt.Sprintf("arn:aws:organizations::000000000000:account/o-example/%s", accountID)),
+		Name:   aws.String(fmt.Sprintf("account-%s", accountID)),
+		Status: status,
+	}
+}
@@ -0,0 +1,207 @@
+// Package organizations implements methods to discover AWS accounts of an AWS Organization
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_organizations "github.com/aws/aws-sdk-go-v2/service/organizations"
+	aws_organizations_types "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var tracer = otel.Tracer("github/qonto/prometheus-rds-exporter/internal/app/organizations")
+
+type Configuration struct {
+	// OrganizationalUnits restricts accounts to those in these organizational units (including nested ones)
+	OrganizationalUnits []string
+
+	// TagSelections restricts accounts to those having these tags
+	TagSelections map[string][]string
+}
+
+// Account is an active AWS account of the organization
+type Account struct {
+	ID   string
+	Arn  string
+	Name string
+}
+
+type OrganizationsClient interface {
+	ListAccounts(ctx context.Context, input *aws_organizations.ListAccountsInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListAccountsOutput, error)
+	ListAccountsForParent(ctx context.Context, input *aws_organizations.ListAccountsForParentInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListAccountsForParentOutput, error)
+	ListOrganizationalUnitsForParent(ctx context.Context, input *aws_organizations.ListOrganizationalUnitsForParentInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListOrganizationalUnitsForParentOutput, error)
+	ListTagsForResource(ctx context.Context, input *aws_organizations.ListTagsForResourceInput, optFns ...func(*aws_organizations.Options)) (*aws_organizations.ListTagsForResourceOutput, error)
+}
+
+func NewFetcher(ctx context.Context, client OrganizationsClient, logger slog.Logger, configuration Configuration) *organizationsFetcher {
+	return &organizationsFetcher{
+		ctx:           ctx,
+		client:        client,
+		logger:        &logger,
+		configuration: configuration,
+	}
+}
+
+type organizationsFetcher struct {
+	ctx           context.Context
+	logger        *slog.Logger
+	client        OrganizationsClient
+	configuration Configuration
+}
+
+// GetAccounts returns active accounts of the organization matching the configuration
+func (o *organizationsFetcher) GetAccounts() ([]Account, error) {
+	ctx, span := tracer.Start(o.ctx, "discover-accounts")
+	defer span.End()
+
+	var (
+		accounts []aws_organizations_types.Account
+		err      error
+	)
+
+	if len(o.configuration.OrganizationalUnits) > 0 {
+		accounts, err = o.listOrganizationalUnitsAccounts(ctx, o.configuration.OrganizationalUnits)
+	} else {
+		accounts, err = o.listAccounts(ctx)
+	}
+
+	if err != nil {
+		span.SetStatus(codes.Error, "can't list accounts")
+		span.RecordError(err)
+
+		return nil, err
+	}
+
+	var result []Account
+
+	for _, account := range accounts {
+		if account.Status != aws_organizations_types.AccountStatusActive {
+			o.logger.Debug("ignore inactive account", "aws_account_id", aws.ToString(account.Id), "status", account.Status)
+
+			continue
+		}
+
+		if len(o.configuration.TagSelections) > 0 {
+			selected, err := o.hasSelectedTags(ctx, aws.ToString(account.Id))
+			if err != nil {
+				span.SetStatus(codes.Error, "can't list account tags")
+				span.RecordError(err)
+
+				return nil, err
+			}
+
+			if !selected {
+				continue
+			}
+		}
+
+		result = append(result, Account{
+			ID:   aws.ToString(account.Id),
+			Arn:  aws.ToString(account.Arn),
+			Name: aws.ToString(account.Name),
+		})
+	}
+
+	span.SetStatus(codes.Ok, "accounts discovered")
+
+	return result, nil
+}
+
+func (o *organizationsFetcher) listAccounts(ctx context.Context) ([]aws_organizations_types.Account, error) {
+	var accounts []aws_organizations_types.Account
+
+	paginator := aws_organizations.NewListAccountsPaginator(o.client, &aws_organizations.ListAccountsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("can't list accounts: %w", err)
+		}
+
+		accounts = append(accounts, output.Accounts...)
+	}
+
+	return accounts, nil
+}
+
+// listOrganizationalUnitsAccounts returns accounts of organizational units and their nested organizational units
+func (o *organizationsFetcher) listOrganizationalUnitsAccounts(ctx context.Context, organizationalUnits []string) ([]aws_organizations_types.Account, error) {
+	var accounts []aws_organizations_types.Account
+
+	for _, organizationalUnit := range organizationalUnits {
+		paginator := aws_organizations.NewListAccountsForParentPaginator(o.client, &aws_organizations.ListAccountsForParentInput{
+			ParentId: aws.String(organizationalUnit),
+		})
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("can't list accounts of %s: %w", organizationalUnit, err)
+			}
+
+			accounts = append(accounts, output.Accounts...)
+		}
+
+		var children []string
+
+		childrenPaginator := aws_organizations.NewListOrganizationalUnitsForParentPaginator(o.client, &aws_organizations.ListOrganizationalUnitsForParentInput{
+			ParentId: aws.String(organizationalUnit),
+		})
+		for childrenPaginator.HasMorePages() {
+			output, err := childrenPaginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("can't list organizational units of %s: %w", organizationalUnit, err)
+			}
+
+			for _, child := range output.OrganizationalUnits {
+				children = append(children, aws.ToString(child.Id))
+			}
+		}
+
+		if len(children) > 0 {
+			childrenAccounts, err := o.listOrganizationalUnitsAccounts(ctx, children)
+			if err != nil {
+				return nil, err
+			}
+
+			accounts = append(accounts, childrenAccounts...)
+		}
+	}
+
+	return accounts, nil
+}
+
+// hasSelectedTags returns true if the account has at least one of the selected values for each selected tag
+func (o *organizationsFetcher) hasSelectedTags(ctx context.Context, accountID string) (bool, error) {
+	tags := make(map[string]string)
+
+	paginator := aws_organizations.NewListTagsForResourcePaginator(o.client, &aws_organizations.ListTagsForResourceInput{
+		ResourceId: aws.String(accountID),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, fmt.Errorf("can't list tags of account %s: %w", accountID, err)
+		}
+
+		for _, tag := range output.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
+	for key, values := range o.configuration.TagSelections {
+		value, found := tags[key]
+		if !found {
+			return false, nil
+		}
+
+		if len(values) > 0 && !slices.Contains(values, value) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,72 @@
+package organizations_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	aws_organizations_types "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/organizations"
+	mock "github.com/qonto/prometheus-rds-exporter/internal/app/organizations/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accountIDs(accounts []organizations.Account) []string {
+	var result []string
+
+	for _, account := range accounts {
+		result = append(result, account.ID)
+	}
+
+	return result
+}
+
+func TestGetAccounts(t *testing.T) {
+	logger := slog.Default()
+
+	client := mock.NewOrganizationsClient().
+		WithOrganizationalUnit("ou-production", "r-root").
+		WithOrganizationalUnit("ou-production-eu", "ou-production").
+		WithAccount(mock.NewAccount("111111111111", aws_organizations_types.AccountStatusActive), "r-root", map[string]string{"environment": "sandbox"}).
+		WithAccount(mock.NewAccount("222222222222", aws_organizations_types.AccountStatusActive), "ou-production", map[string]string{"environment": "production"}).
+		WithAccount(mock.NewAccount("333333333333", aws_organizations_types.AccountStatusActive), "ou-production-eu", map[string]string{"environment": "production", "monitoring": "disabled"}).
+		WithAccount(mock.NewAccount("444444444444", aws_organizations_types.AccountStatusSuspended), "ou-production", nil)
+
+	testCases := []struct {
+		name          string
+		configuration organizations.Configuration
+		expected      []string
+	}{
+		{
+			name:     "All active accounts",
+			expected: []string{"111111111111", "222222222222", "333333333333"},
+		},
+		{
+			name:          "Accounts of organizational unit and nested organizational units",
+			configuration: organizations.Configuration{OrganizationalUnits: []string{"ou-production"}},
+			expected:      []string{"222222222222", "333333333333"},
+		},
+		{
+			name:          "Accounts with tag value",
+			configuration: organizations.Configuration{TagSelections: map[string][]string{"environment": {"production"}}},
+			expected:      []string{"222222222222", "333333333333"},
+		},
+		{
+			name:          "Accounts with all tags",
+			configuration: organizations.Configuration{TagSelections: map[string][]string{"environment": {"production"}, "monitoring": {"enabled"}}},
+			expected:      nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fetcher := organizations.NewFetcher(context.TODO(), client, *logger, tc.configuration)
+
+			accounts, err := fetcher.GetAccounts()
+			require.NoError(t, err, "GetAccounts must succeed")
+			assert.ElementsMatch(t, tc.expected, accountIDs(accounts), "Unexpected accounts")
+		})
+	}
+}
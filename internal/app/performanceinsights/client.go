@@ -0,0 +1,12 @@
+package performanceinsights
+
+import (
+	"context"
+
+	aws_pi "github.com/aws/aws-sdk-go-v2/service/pi"
+)
+
+// Client is the subset of the Performance Insights API used by the exporter
+type Client interface {
+	GetResourceMetrics(context.Context, *aws_pi.GetResourceMetricsInput, ...func(*aws_pi.Options)) (*aws_pi.GetResourceMetricsOutput, error)
+}
@@ -0,0 +1,63 @@
+// Package mocks contains mock for Performance Insights client
+package mocks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_pi "github.com/aws/aws-sdk-go-v2/service/pi"
+	aws_pi_types "github.com/aws/aws-sdk-go-v2/service/pi/types"
+)
+
+var ErrGetResourceMetrics = errors.New("GetResourceMetrics failed")
+
+type PerformanceInsightsClient struct {
+	Metrics           map[string][]aws_pi_types.MetricKeyDataPoints // Results by DbiResourceId
+	FailingIdentifier string                                        // GetResourceMetrics fails for this DbiResourceId
+	Inputs            *[]aws_pi.GetResourceMetricsInput             // Records requests when set
+}
+
+func (m PerformanceInsightsClient) GetResourceMetrics(_ context.Context, input *aws_pi.GetResourceMetricsInput, _ ...func(*aws_pi.Options)) (*aws_pi.GetResourceMetricsOutput, error) {
+	if m.Inputs != nil {
+		*m.Inputs = append(*m.Inputs, *input)
+	}
+
+	identifier := aws.ToString(input.Identifier)
+
+	if m.FailingIdentifier != "" && identifier == m.FailingIdentifier {
+		return nil, ErrGetResourceMetrics
+	}
+
+	return &aws_pi.GetResourceMetricsOutput{MetricList: m.Metrics[identifier]}, nil
+}
+
+// NewWaitEventLoad returns the database load result of a wait event
+func NewWaitEventLoad(eventType string, name string, value float64) aws_pi_types.MetricKeyDataPoints {
+	return newLoad(map[string]string{"db.wait_event.type": eventType, "db.wait_event.name": name}, value)
+}
+
+// NewSQLLoad returns the database load result of a tokenized SQL statement
+func NewSQLLoad(id string, statement string, value float64) aws_pi_types.MetricKeyDataPoints {
+	return newLoad(map[string]string{"db.sql_tokenized.id": id, "db.sql_tokenized.statement": statement}, value)
+}
+
+// NewTotalLoad returns the total database load result of a grouped query
+func NewTotalLoad(value float64) aws_pi_types.MetricKeyDataPoints {
+	return newLoad(nil, value)
+}
+
+func newLoad(dimensions map[string]string, value float64) aws_pi_types.MetricKeyDataPoints {
+	now := time.Now()
+	previousValue := value + 1
+
+	return aws_pi_types.MetricKeyDataPoints{
+		Key: &aws_pi_types.ResponseResourceMetricKey{Metric: aws.String("db.load.avg"), Dimensions: dimensions},
+		DataPoints: []aws_pi_types.DataPoint{
+			{Timestamp: aws.Time(now.Add(-3 * time.Minute)), Value: &previousValue},
+			{Timestamp: aws.Time(now.Add(-2 * time.Minute)), Value: &value},
+			{Timestamp: aws.Time(now.Add(-time.Minute))}, // Latest period without data yet
+		},
+	}
+}
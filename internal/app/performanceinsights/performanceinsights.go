@@ -0,0 +1,222 @@
+// Package performanceinsights implements methods to retrieve RDS Performance Insights information
+package performanceinsights
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_pi "github.com/aws/aws-sdk-go-v2/service/pi"
+	aws_pi_types "github.com/aws/aws-sdk-go-v2/service/pi/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var tracer = otel.Tracer("github/qonto/prometheus-rds-exporter/internal/app/performanceinsights")
+
+const (
+	dbLoadMetric = "db.load.avg"
+
+	waitEventGroup         = "db.wait_event"
+	waitEventNameDimension = "db.wait_event.name"
+	waitEventTypeDimension = "db.wait_event.type"
+
+	sqlGroup              = "db.sql_tokenized"
+	sqlIDDimension        = "db.sql_tokenized.id"
+	sqlStatementDimension = "db.sql_tokenized.statement"
+
+	// Performance Insights returns at most 25 dimensions per group
+	DefaultTopN = 10
+	MaxTopN     = 25
+
+	// maxStatementLength truncates SQL statements exported as label
+	maxStatementLength = 200
+
+	period      int32 = 60
+	queryWindow       = 5 * time.Minute // Several periods to get the latest datapoint despite Performance Insights delay
+)
+
+// Instance is an RDS instance with Performance Insights enabled
+type Instance struct {
+	DBIdentifier  string
+	DbiResourceID string
+}
+
+// WaitEventLoad is the database load of a wait event
+type WaitEventLoad struct {
+	Type  string
+	Name  string
+	Value float64 // Average active sessions
+}
+
+// SQLLoad is the database load of a tokenized SQL statement
+type SQLLoad struct {
+	ID        string
+	Statement string
+	Value     float64 // Average active sessions
+}
+
+// InstanceMetrics contains the top wait events and SQL statements of the database load of an instance
+type InstanceMetrics struct {
+	WaitEvents []WaitEventLoad
+	SQL        []SQLLoad
+}
+
+type Metrics struct {
+	Instances map[string]InstanceMetrics
+}
+
+func NewFetcher(ctx context.Context, client Client, logger slog.Logger, topN int) *Fetcher {
+	if topN <= 0 {
+		topN = DefaultTopN
+	}
+
+	return &Fetcher{
+		ctx:    ctx,
+		client: client,
+		logger: &logger,
+		topN:   int32(min(topN, MaxTopN)), //nolint:gosec // topN is between 1 and MaxTopN
+	}
+}
+
+type Fetcher struct {
+	ctx    context.Context
+	client Client
+	logger *slog.Logger
+	topN   int32 // Number of top wait events and SQL statements of each instance
+}
+
+// GetInstancesMetrics returns the top wait events and SQL statements of instances by dbidentifier
+// A failing instance doesn't prevent fetching other instances, errors are returned with fetched metrics
+func (f *Fetcher) GetInstancesMetrics(instances []Instance) (Metrics, error) {
+	ctx, span := tracer.Start(f.ctx, "collect-performance-insights-metrics")
+	defer span.End()
+
+	metrics := Metrics{Instances: make(map[string]InstanceMetrics, len(instances))}
+
+	var errs []error
+
+	for _, instance := range instances {
+		instanceMetrics, err := f.getInstanceMetrics(ctx, instance)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't get Performance Insights metrics of %s: %w", instance.DBIdentifier, err))
+
+			continue
+		}
+
+		metrics.Instances[instance.DBIdentifier] = instanceMetrics
+	}
+
+	span.SetAttributes(attribute.Int("qonto.prometheus_rds_exporter.instance_count", len(metrics.Instances)))
+
+	if len(errs) > 0 {
+		err := errors.Join(errs...)
+
+		span.SetStatus(codes.Error, "can't get Performance Insights metrics")
+		span.RecordError(err)
+
+		return metrics, err
+	}
+
+	span.SetStatus(codes.Ok, "metrics fetched")
+
+	return metrics, nil
+}
+
+func (f *Fetcher) getInstanceMetrics(ctx context.Context, instance Instance) (InstanceMetrics, error) {
+	now := time.Now()
+
+	input := &aws_pi.GetResourceMetricsInput{
+		ServiceType: aws_pi_types.ServiceTypeRds,
+		Identifier:  aws.String(instance.DbiResourceID),
+		MetricQueries: []aws_pi_types.MetricQuery{
+			{Metric: aws.String(dbLoadMetric), GroupBy: &aws_pi_types.DimensionGroup{Group: aws.String(waitEventGroup), Limit: aws.Int32(f.topN)}},
+			{Metric: aws.String(dbLoadMetric), GroupBy: &aws_pi_types.DimensionGroup{Group: aws.String(sqlGroup), Dimensions: []string{sqlIDDimension, sqlStatementDimension}, Limit: aws.Int32(f.topN)}},
+		},
+		StartTime:       aws.Time(now.Add(-queryWindow)),
+		EndTime:         aws.Time(now),
+		PeriodInSeconds: aws.Int32(period),
+	}
+
+	var metrics InstanceMetrics
+
+	for {
+		output, err := f.client.GetResourceMetrics(ctx, input)
+		if err != nil {
+			return InstanceMetrics{}, fmt.Errorf("can't get resource metrics: %w", err)
+		}
+
+		for _, result := range output.MetricList {
+			value, found := latestValue(result.DataPoints)
+			if !found {
+				continue
+			}
+
+			if result.Key == nil {
+				continue
+			}
+
+			dimensions := result.Key.Dimensions
+
+			switch {
+			case dimensions[waitEventNameDimension] != "":
+				metrics.WaitEvents = append(metrics.WaitEvents, WaitEventLoad{
+					Type:  dimensions[waitEventTypeDimension],
+					Name:  dimensions[waitEventNameDimension],
+					Value: value,
+				})
+			case dimensions[sqlIDDimension] != "":
+				metrics.SQL = append(metrics.SQL, SQLLoad{
+					ID:        dimensions[sqlIDDimension],
+					Statement: truncate(dimensions[sqlStatementDimension], maxStatementLength),
+					Value:     value,
+				})
+			default:
+				// Total database load of the query, already available as DBLoad CloudWatch metric
+			}
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+
+		input.NextToken = output.NextToken
+	}
+
+	return metrics, nil
+}
+
+// latestValue returns the value of the most recent datapoint with data
+func latestValue(datapoints []aws_pi_types.DataPoint) (float64, bool) {
+	var (
+		value     float64
+		timestamp time.Time
+		found     bool
+	)
+
+	for _, datapoint := range datapoints {
+		if datapoint.Value == nil || (found && aws.ToTime(datapoint.Timestamp).Before(timestamp)) {
+			continue
+		}
+
+		value = *datapoint.Value
+		timestamp = aws.ToTime(datapoint.Timestamp)
+		found = true
+	}
+
+	return value, found
+}
+
+// truncate returns the first length characters of the string
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length])
+}
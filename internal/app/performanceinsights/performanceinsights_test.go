@@ -0,0 +1,111 @@
+package performanceinsights_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_pi "github.com/aws/aws-sdk-go-v2/service/pi"
+	aws_pi_types "github.com/aws/aws-sdk-go-v2/service/pi/types"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/performanceinsights"
+	mock "github.com/qonto/prometheus-rds-exporter/internal/app/performanceinsights/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInstancesMetrics(t *testing.T) {
+	t.Parallel()
+
+	instance := performanceinsights.Instance{DBIdentifier: "db-1", DbiResourceID: "db-ABCDEFGHIJKL"}
+	longStatement := "SELECT " + strings.Repeat("a", 300) + " FROM t"
+
+	client := mock.PerformanceInsightsClient{
+		Metrics: map[string][]aws_pi_types.MetricKeyDataPoints{
+			instance.DbiResourceID: {
+				mock.NewTotalLoad(3),
+				mock.NewWaitEventLoad("IO", "IO:DataFileRead", 1.5),
+				mock.NewWaitEventLoad("CPU", "CPU", 0.5),
+				mock.NewSQLLoad("ABC123", "SELECT * FROM users WHERE id = ?", 1.25),
+				mock.NewSQLLoad("DEF456", longStatement, 0.75),
+			},
+		},
+	}
+
+	fetcher := performanceinsights.NewFetcher(context.TODO(), client, slog.Logger{}, 0)
+
+	metrics, err := fetcher.GetInstancesMetrics([]performanceinsights.Instance{instance})
+	require.NoError(t, err, "GetInstancesMetrics must succeed")
+	require.Contains(t, metrics.Instances, instance.DBIdentifier, "Instance must be returned")
+
+	m := metrics.Instances[instance.DBIdentifier]
+
+	expectedWaitEvents := []performanceinsights.WaitEventLoad{
+		{Type: "IO", Name: "IO:DataFileRead", Value: 1.5},
+		{Type: "CPU", Name: "CPU", Value: 0.5},
+	}
+	assert.Equal(t, expectedWaitEvents, m.WaitEvents, "Wait events mismatch, total load must be skipped and latest value used")
+
+	require.Len(t, m.SQL, 2, "SQL statements mismatch")
+	assert.Equal(t, performanceinsights.SQLLoad{ID: "ABC123", Statement: "SELECT * FROM users WHERE id = ?", Value: 1.25}, m.SQL[0], "SQL statement mismatch")
+	assert.Equal(t, "DEF456", m.SQL[1].ID, "SQL ID mismatch")
+	assert.Len(t, m.SQL[1].Statement, 200, "Long SQL statement must be truncated")
+	assert.True(t, strings.HasPrefix(longStatement, m.SQL[1].Statement), "Truncated statement must be the beginning of the statement")
+}
+
+func TestGetInstancesMetricsWithFailingInstance(t *testing.T) {
+	t.Parallel()
+
+	failingInstance := performanceinsights.Instance{DBIdentifier: "db-1", DbiResourceID: "db-FAILING"}
+	instance := performanceinsights.Instance{DBIdentifier: "db-2", DbiResourceID: "db-WORKING"}
+
+	client := mock.PerformanceInsightsClient{
+		Metrics: map[string][]aws_pi_types.MetricKeyDataPoints{
+			instance.DbiResourceID: {mock.NewWaitEventLoad("CPU", "CPU", 0.5)},
+		},
+		FailingIdentifier: failingInstance.DbiResourceID,
+	}
+
+	fetcher := performanceinsights.NewFetcher(context.TODO(), client, slog.Logger{}, 0)
+
+	metrics, err := fetcher.GetInstancesMetrics([]performanceinsights.Instance{failingInstance, instance})
+	require.ErrorIs(t, err, mock.ErrGetResourceMetrics, "GetInstancesMetrics must return instance errors")
+	assert.ErrorContains(t, err, "db-1", "Error must contain the failing instance")
+
+	assert.NotContains(t, metrics.Instances, failingInstance.DBIdentifier, "Failing instance must not be returned")
+	assert.Contains(t, metrics.Instances, instance.DBIdentifier, "Other instances must be returned")
+}
+
+func TestTopN(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		topN     int
+		expected int32
+	}{
+		{"default", 0, performanceinsights.DefaultTopN},
+		{"custom", 5, 5},
+		{"capped", 100, performanceinsights.MaxTopN},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var inputs []aws_pi.GetResourceMetricsInput
+
+			client := mock.PerformanceInsightsClient{Inputs: &inputs}
+			fetcher := performanceinsights.NewFetcher(context.TODO(), client, slog.Logger{}, tc.topN)
+
+			_, err := fetcher.GetInstancesMetrics([]performanceinsights.Instance{{DBIdentifier: "db-1", DbiResourceID: "db-ABC"}})
+			require.NoError(t, err, "GetInstancesMetrics must succeed")
+			require.Len(t, inputs, 1, "One request is expected")
+
+			for _, query := range inputs[0].MetricQueries {
+				assert.Equal(t, tc.expected, aws.ToInt32(query.GroupBy.Limit), "Limit mismatch for %s", aws.ToString(query.GroupBy.Group))
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_rds "github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/patrickmn/go-cache"
+	"github.com/qonto/prometheus-rds-exporter/internal/infra/awsapi"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 )
@@ -185,7 +186,7 @@ func (s *EngineSupportService) FetchEngineVersionLifecycles(ctx context.Context,
 					"required_permission", "rds:DescribeDBMajorEngineVersions")
 			} else if strings.Contains(err.Error(), "InvalidParameterValue") {
 				s.logger.Error("Invalid engine parameter provided to AWS API", "engine", engine)
-			} else if strings.Contains(err.Error(), "RequestLimitExceeded") || strings.Contains(err.Error(), "Throttling") {
+			} else if awsapi.IsThrottle(err) {
 				s.logger.Error("AWS API rate limit exceeded for DescribeDBMajorEngineVersions", "engine", engine)
 			}
 
@@ -236,6 +237,13 @@ func (s *EngineSupportService) GenerateCacheKey(engine string) string {
 	return fmt.Sprintf("engine_lifecycle_%s", engine)
 }
 
+// IsCached returns true if lifecycle data of the engine is cached, so it can be retrieved without AWS API call
+func (s *EngineSupportService) IsCached(engine string) bool {
+	_, found := s.cache.Get(s.GenerateCacheKey(engine))
+
+	return found
+}
+
 // GetCache returns the cache instance for testing purposes
 func (s *EngineSupportService) GetCache() *cache.Cache {
 	return s.cache
@@ -22,6 +22,12 @@ type RDSClient struct {
 	DescribeDBMajorEngineVersionsOutput     *aws_rds.DescribeDBMajorEngineVersionsOutput
 	DescribeDBMajorEngineVersionsError      error
 	DescribeDBMajorEngineVersionsCallCount  int
+	DescribeDBInstancesCallCount            int
+	DescribeDBInstancesTimeout              bool // Simulates an unresponsive AWS API that only returns when the request is cancelled
+	DescribeDBProxiesOutput                 *aws_rds.DescribeDBProxiesOutput
+	DescribeDBProxiesError                  error
+	DBProxyTargets                          map[string][]aws_rds_types.DBProxyTarget // Targets of the default target group by proxy name
+	DBProxyTargetsErrors                    map[string]error                         // DescribeDBProxyTargets errors by proxy name
 	Error                                   error
 }
 
@@ -45,6 +51,11 @@ func NewRDSClient() *RDSClient {
 		DescribeDBMajorEngineVersionsOutput: &aws_rds.DescribeDBMajorEngineVersionsOutput{
 			DBMajorEngineVersions: []aws_rds_types.DBMajorEngineVersion{},
 		},
+		DescribeDBProxiesOutput: &aws_rds.DescribeDBProxiesOutput{
+			DBProxies: []aws_rds_types.DBProxy{},
+		},
+		DBProxyTargets:       make(map[string][]aws_rds_types.DBProxyTarget),
+		DBProxyTargetsErrors: make(map[string]error),
 	}
 
 	return client
@@ -92,10 +103,35 @@ func (m *RDSClient) WithDescribeDBMajorEngineVersionsError(err error) *RDSClient
 	return m
 }
 
+// WithDBProxy adds a proxy with targets in its default target group
+func (m *RDSClient) WithDBProxy(proxy aws_rds_types.DBProxy, targets ...aws_rds_types.DBProxyTarget) *RDSClient {
+	m.DescribeDBProxiesOutput.DBProxies = append(m.DescribeDBProxiesOutput.DBProxies, proxy)
+	m.DBProxyTargets[*proxy.DBProxyName] = targets
+
+	return m
+}
+
+func (m *RDSClient) WithDescribeDBProxiesError(err error) *RDSClient {
+	m.DescribeDBProxiesError = err
+
+	return m
+}
+
+// WithDescribeDBProxyTargetsError makes DescribeDBProxyTargets fail for the proxy
+func (m *RDSClient) WithDescribeDBProxyTargetsError(proxyName string, err error) *RDSClient {
+	m.DBProxyTargetsErrors[proxyName] = err
+
+	return m
+}
+
 func (m *RDSClient) GetDescribeDBMajorEngineVersionsCallCount() int {
 	return m.DescribeDBMajorEngineVersionsCallCount
 }
 
+func (m *RDSClient) GetDescribeDBInstancesCallCount() int {
+	return m.DescribeDBInstancesCallCount
+}
+
 func (m RDSClient) DescribeDBClusters(ctx context.Context, params *aws_rds.DescribeDBClustersInput, optFns ...func(*aws_rds.Options)) (*aws_rds.DescribeDBClustersOutput, error) {
 	return m.DescribeDBClustersOutput, nil
 }
@@ -114,7 +150,15 @@ func (m RDSClient) DescribeDBLogFiles(ctx context.Context, input *aws_rds.Descri
 	return m.DescribeDBLogFilesOutput, m.DescribeDBLogFilesOutputError
 }
 
-func (m RDSClient) DescribeDBInstances(context.Context, *aws_rds.DescribeDBInstancesInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBInstancesOutput, error) {
+func (m *RDSClient) DescribeDBInstances(ctx context.Context, _ *aws_rds.DescribeDBInstancesInput, _ ...func(*aws_rds.Options)) (*aws_rds.DescribeDBInstancesOutput, error) {
+	m.DescribeDBInstancesCallCount++
+
+	if m.DescribeDBInstancesTimeout {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
 	return m.DescribeDBInstancesOutput, nil
 }
 
@@ -127,6 +171,41 @@ func (m *RDSClient) DescribeDBMajorEngineVersions(context.Context, *aws_rds.Desc
 	return m.DescribeDBMajorEngineVersionsOutput, m.DescribeDBMajorEngineVersionsError
 }
 
+func (m RDSClient) DescribeDBProxies(context.Context, *aws_rds.DescribeDBProxiesInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxiesOutput, error) {
+	if m.DescribeDBProxiesError != nil {
+		return nil, m.DescribeDBProxiesError
+	}
+
+	return m.DescribeDBProxiesOutput, nil
+}
+
+func (m RDSClient) DescribeDBProxyTargetGroups(_ context.Context, input *aws_rds.DescribeDBProxyTargetGroupsInput, _ ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxyTargetGroupsOutput, error) {
+	return &aws_rds.DescribeDBProxyTargetGroupsOutput{
+		TargetGroups: []aws_rds_types.DBProxyTargetGroup{
+			{
+				DBProxyName:     input.DBProxyName,
+				TargetGroupName: aws.String(DefaultProxyTargetGroup),
+				IsDefault:       aws.Bool(true),
+				Status:          aws.String("available"),
+			},
+		},
+	}, nil
+}
+
+func (m RDSClient) DescribeDBProxyTargets(_ context.Context, input *aws_rds.DescribeDBProxyTargetsInput, _ ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxyTargetsOutput, error) {
+	if err, ok := m.DBProxyTargetsErrors[aws.ToString(input.DBProxyName)]; ok {
+		return nil, err
+	}
+
+	output := &aws_rds.DescribeDBProxyTargetsOutput{}
+
+	if aws.ToString(input.TargetGroupName) == DefaultProxyTargetGroup {
+		output.Targets = m.DBProxyTargets[aws.ToString(input.DBProxyName)]
+	}
+
+	return output, nil
+}
+
 // RandomString returns a random alphanumeric string of the specified length
 func RandomString(length int) string {
 	buf := make([]byte, length)
@@ -171,6 +250,7 @@ func NewRdsInstance() *aws_rds_types.DBInstance {
 		EngineVersion:              aws.String("14.9"),
 		Iops:                       aws.Int32(3000),
 		MaxAllocatedStorage:        aws.Int32(10),
+		MonitoringInterval:         aws.Int32(60),
 		MultiAZ:                    aws.Bool(true),
 		PerformanceInsightsEnabled: aws.Bool(true),
 		PubliclyAccessible:         aws.Bool(true),
@@ -259,3 +339,39 @@ func NewMultiAZCluster() *aws_rds_types.DBCluster {
 
 	return cluster
 }
+
+// DefaultProxyTargetGroup is the name of the target group of mocked proxies
+const DefaultProxyTargetGroup = "default"
+
+func NewDBProxy() *aws_rds_types.DBProxy {
+	awsRegion := "eu-west-3"
+	awsAccountID := "123456789012"
+	DBProxyName := RandomString(10)
+	arn := fmt.Sprintf("arn:aws:rds:%s:%s:db-proxy:prx-%s", awsRegion, awsAccountID, DBProxyName)
+
+	return &aws_rds_types.DBProxy{
+		DBProxyArn:   aws.String(arn),
+		DBProxyName:  aws.String(DBProxyName),
+		EngineFamily: aws.String("POSTGRESQL"),
+		RequireTLS:   aws.Bool(true),
+		Status:       aws_rds_types.DBProxyStatusAvailable,
+	}
+}
+
+// NewDBProxyTarget returns a proxy target of the instance with the health state
+func NewDBProxyTarget(dbidentifier string, state aws_rds_types.TargetState) *aws_rds_types.DBProxyTarget {
+	target := &aws_rds_types.DBProxyTarget{
+		RdsResourceId: aws.String(dbidentifier),
+		Role:          aws_rds_types.TargetRoleReadWrite,
+		Type:          aws_rds_types.TargetTypeRdsInstance,
+		TargetHealth: &aws_rds_types.TargetHealth{
+			State: state,
+		},
+	}
+
+	if state == aws_rds_types.TargetStateUnavailable {
+		target.TargetHealth.Reason = aws_rds_types.TargetHealthReasonConnectionFailed
+	}
+
+	return target
+}
@@ -0,0 +1,185 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_rds "github.com/aws/aws-sdk-go-v2/service/rds"
+	aws_rds_types "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Proxy target health states
+const (
+	ProxyTargetStateAvailable   int = 1
+	ProxyTargetStateRegistering int = 0
+	ProxyTargetStateUnavailable int = -1
+	ProxyTargetStateUnknown     int = -2
+)
+
+var proxyTargetStates = map[aws_rds_types.TargetState]int{
+	aws_rds_types.TargetStateAvailable:   ProxyTargetStateAvailable,
+	aws_rds_types.TargetStateRegistering: ProxyTargetStateRegistering,
+	aws_rds_types.TargetStateUnavailable: ProxyTargetStateUnavailable,
+}
+
+type ProxyMetrics struct {
+	// The Amazon Resource Name (ARN) for the proxy.
+	Arn string
+
+	// The kind of database engine that the proxy can connect to (eg. POSTGRESQL or MYSQL).
+	EngineFamily string
+
+	// The current status of this proxy (eg. available, modifying, incompatible-network).
+	Status string
+
+	// Whether the proxy requires TLS connections.
+	RequireTLS bool
+
+	Targets []ProxyTargetMetrics
+}
+
+type ProxyTargetMetrics struct {
+	// The identifier of the target, an instance identifier or an Aurora cluster identifier.
+	DBIdentifier string
+
+	// The target group of the proxy the target belongs to.
+	TargetGroup string
+
+	// The type of target (RDS_INSTANCE, TRACKED_CLUSTER or RDS_SERVERLESS_ENDPOINT).
+	Type string
+
+	// The role of the target in the target group (READ_WRITE, READ_ONLY or UNKNOWN).
+	Role string
+
+	// Health state of the target, see ProxyTargetState* constants.
+	State int
+
+	// Reason of an unavailable target (eg. CONNECTION_FAILED, AUTH_FAILURE).
+	Reason string
+}
+
+// GetProxiesMetrics returns RDS proxies with their targets health by proxy name
+// Proxies collected before an error are returned with the error
+func (r *RDSFetcher) GetProxiesMetrics() (map[string]ProxyMetrics, error) {
+	ctx, span := tracer.Start(r.ctx, "collect-proxy-metrics")
+	defer span.End()
+
+	proxies := make(map[string]ProxyMetrics)
+
+	var errs []error
+
+	paginator := aws_rds.NewDescribeDBProxiesPaginator(r.client, &aws_rds.DescribeDBProxiesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, "can't describe RDS proxies")
+			span.RecordError(err)
+
+			return nil, fmt.Errorf("can't describe RDS proxies: %w", err)
+		}
+
+		for _, proxy := range output.DBProxies {
+			proxyName := aws.ToString(proxy.DBProxyName)
+
+			// Proxies whose targets can't be fetched are returned without targets
+			targets, err := r.getProxyTargets(ctx, proxyName)
+			if err != nil {
+				span.RecordError(err)
+				r.logger.Warn("can't get RDS proxy targets, skipping its targets", "proxy", proxyName, "reason", err)
+
+				errs = append(errs, err)
+			}
+
+			proxies[proxyName] = ProxyMetrics{
+				Arn:          aws.ToString(proxy.DBProxyArn),
+				EngineFamily: aws.ToString(proxy.EngineFamily),
+				Status:       string(proxy.Status),
+				RequireTLS:   aws.ToBool(proxy.RequireTLS),
+				Targets:      targets,
+			}
+		}
+	}
+
+	span.SetAttributes(attribute.Int("qonto.prometheus_rds_exporter.proxy_count", len(proxies)))
+
+	if len(errs) > 0 {
+		span.SetStatus(codes.Error, "can't get targets of some RDS proxies")
+
+		return proxies, errors.Join(errs...)
+	}
+
+	span.SetStatus(codes.Ok, "proxies fetched")
+
+	return proxies, nil
+}
+
+// getProxyTargets returns targets of all target groups of the proxy
+func (r *RDSFetcher) getProxyTargets(ctx context.Context, proxyName string) ([]ProxyTargetMetrics, error) {
+	var targets []ProxyTargetMetrics
+
+	targetGroupsPaginator := aws_rds.NewDescribeDBProxyTargetGroupsPaginator(r.client, &aws_rds.DescribeDBProxyTargetGroupsInput{DBProxyName: aws.String(proxyName)})
+	for targetGroupsPaginator.HasMorePages() {
+		output, err := targetGroupsPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("can't describe target groups of RDS proxy %s: %w", proxyName, err)
+		}
+
+		for _, targetGroup := range output.TargetGroups {
+			targetGroupName := aws.ToString(targetGroup.TargetGroupName)
+
+			input := &aws_rds.DescribeDBProxyTargetsInput{
+				DBProxyName:     aws.String(proxyName),
+				TargetGroupName: aws.String(targetGroupName),
+			}
+
+			targetsPaginator := aws_rds.NewDescribeDBProxyTargetsPaginator(r.client, input)
+			for targetsPaginator.HasMorePages() {
+				output, err := targetsPaginator.NextPage(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("can't describe targets of RDS proxy %s: %w", proxyName, err)
+				}
+
+				for _, target := range output.Targets {
+					targets = append(targets, newProxyTargetMetrics(targetGroupName, target))
+				}
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// IsInstance returns true if the target is an RDS instance, other targets are Aurora clusters or serverless endpoints
+func (t ProxyTargetMetrics) IsInstance() bool {
+	return t.Type == string(aws_rds_types.TargetTypeRdsInstance)
+}
+
+func newProxyTargetMetrics(targetGroup string, target aws_rds_types.DBProxyTarget) ProxyTargetMetrics {
+	metrics := ProxyTargetMetrics{
+		DBIdentifier: aws.ToString(target.RdsResourceId),
+		TargetGroup:  targetGroup,
+		Type:         string(target.Type),
+		Role:         string(target.Role),
+		State:        ProxyTargetStateUnknown,
+	}
+
+	if target.TargetHealth != nil {
+		metrics.State = GetProxyTargetStateCode(target.TargetHealth.State)
+		metrics.Reason = string(target.TargetHealth.Reason)
+	}
+
+	return metrics
+}
+
+// GetProxyTargetStateCode returns the code of the proxy target health state
+func GetProxyTargetStateCode(state aws_rds_types.TargetState) int {
+	if code, ok := proxyTargetStates[state]; ok {
+		return code
+	}
+
+	return ProxyTargetStateUnknown
+}
@@ -0,0 +1,94 @@
+package rds_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	aws_rds_types "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/qonto/prometheus-rds-exporter/internal/app/rds"
+	mock "github.com/qonto/prometheus-rds-exporter/internal/app/rds/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProxiesMetrics(t *testing.T) {
+	t.Parallel()
+
+	proxy := mock.NewDBProxy()
+	availableTarget := mock.NewDBProxyTarget("db-1", aws_rds_types.TargetStateAvailable)
+	unavailableTarget := mock.NewDBProxyTarget("db-2", aws_rds_types.TargetStateUnavailable)
+
+	client := mock.NewRDSClient().WithDBProxy(*proxy, *availableTarget, *unavailableTarget)
+	fetcher := rds.NewFetcher(context.TODO(), client, nil, slog.Logger{}, rds.Configuration{})
+
+	proxies, err := fetcher.GetProxiesMetrics()
+	require.NoError(t, err, "GetProxiesMetrics must succeed")
+	require.Contains(t, proxies, *proxy.DBProxyName, "Proxy must be returned")
+
+	m := proxies[*proxy.DBProxyName]
+	assert.Equal(t, *proxy.DBProxyArn, m.Arn, "ARN mismatch")
+	assert.Equal(t, "POSTGRESQL", m.EngineFamily, "Engine family mismatch")
+	assert.Equal(t, "available", m.Status, "Status mismatch")
+	assert.True(t, m.RequireTLS, "TLS should be required")
+
+	expectedTargets := []rds.ProxyTargetMetrics{
+		{DBIdentifier: "db-1", TargetGroup: mock.DefaultProxyTargetGroup, Type: "RDS_INSTANCE", Role: "READ_WRITE", State: rds.ProxyTargetStateAvailable},
+		{DBIdentifier: "db-2", TargetGroup: mock.DefaultProxyTargetGroup, Type: "RDS_INSTANCE", Role: "READ_WRITE", State: rds.ProxyTargetStateUnavailable, Reason: "CONNECTION_FAILED"},
+	}
+	assert.Equal(t, expectedTargets, m.Targets, "Targets mismatch")
+}
+
+func TestGetProxiesMetricsError(t *testing.T) {
+	t.Parallel()
+
+	client := mock.NewRDSClient().WithDescribeDBProxiesError(errors.New("AccessDenied"))
+	fetcher := rds.NewFetcher(context.TODO(), client, nil, slog.Logger{}, rds.Configuration{})
+
+	_, err := fetcher.GetProxiesMetrics()
+	require.Error(t, err, "GetProxiesMetrics must fail")
+	assert.ErrorContains(t, err, "can't describe RDS proxies", "Error mismatch")
+}
+
+func TestGetProxiesMetricsWithFailingTargets(t *testing.T) {
+	t.Parallel()
+
+	proxy := mock.NewDBProxy()
+	failingProxy := mock.NewDBProxy()
+	target := mock.NewDBProxyTarget("db-1", aws_rds_types.TargetStateAvailable)
+
+	client := mock.NewRDSClient().
+		WithDBProxy(*proxy, *target).
+		WithDBProxy(*failingProxy, *target).
+		WithDescribeDBProxyTargetsError(*failingProxy.DBProxyName, errors.New("AccessDenied"))
+	fetcher := rds.NewFetcher(context.TODO(), client, nil, *slog.Default(), rds.Configuration{})
+
+	proxies, err := fetcher.GetProxiesMetrics()
+	require.Error(t, err, "GetProxiesMetrics must report failing targets")
+	assert.ErrorContains(t, err, *failingProxy.DBProxyName, "Error must contain the failing proxy")
+
+	require.Contains(t, proxies, *proxy.DBProxyName, "Proxy must be returned")
+	assert.Len(t, proxies[*proxy.DBProxyName].Targets, 1, "Targets of other proxies must be returned")
+
+	require.Contains(t, proxies, *failingProxy.DBProxyName, "Proxy with failing targets must be returned")
+	assert.Empty(t, proxies[*failingProxy.DBProxyName].Targets, "Targets of the failing proxy must be skipped")
+}
+
+func TestGetProxyTargetStateCode(t *testing.T) {
+	testCases := []struct {
+		state    aws_rds_types.TargetState
+		expected int
+	}{
+		{aws_rds_types.TargetStateAvailable, rds.ProxyTargetStateAvailable},
+		{aws_rds_types.TargetStateRegistering, rds.ProxyTargetStateRegistering},
+		{aws_rds_types.TargetStateUnavailable, rds.ProxyTargetStateUnavailable},
+		{"UNUSED", rds.ProxyTargetStateUnknown},
+		{"", rds.ProxyTargetStateUnknown},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, rds.GetProxyTargetStateCode(tc.state), "state code mismatch for %q", tc.state)
+	}
+}
@@ -29,6 +29,10 @@ type Configuration struct {
 	CollectMaintenances       bool
 	CollectClusterMetrics     bool
 	TagSelections             map[string][]string
+
+	// LogsSizeBudget reports whether logs size of a page of instances can be fetched with the given number of AWS API calls
+	// Logs size of the page is not fetched when it returns false. Logs size is always fetched when nil
+	LogsSizeBudget func(calls int) bool
 }
 
 type Metrics struct {
@@ -36,11 +40,6 @@ type Metrics struct {
 	Clusters  map[string]ClusterMetrics
 }
 
-type Statistics struct {
-	RdsAPICall float64
-	TagAPICall float64
-}
-
 type ClusterMetrics struct {
 	// Seconds since cluster creation date.
 	Age float64
@@ -134,6 +133,9 @@ type RdsInstanceMetrics struct {
 	// Maximum provisioned IOPS per GiB for a DB instance.
 	MaxIops int64
 
+	// The interval, in seconds, between Enhanced Monitoring metrics collections (0 when Enhanced Monitoring is disabled).
+	MonitoringInterval int32
+
 	// Indicates whether the Single-AZ DB instance will change to a Multi-AZ deployment.
 	MultiAZ bool
 
@@ -256,6 +258,9 @@ type RDSClient interface {
 	DescribeDBLogFiles(context.Context, *aws_rds.DescribeDBLogFilesInput, ...func(*aws_rds.Options)) (*aws_rds.DescribeDBLogFilesOutput, error)
 	DescribeDBEngineVersions(ctx context.Context, params *aws_rds.DescribeDBEngineVersionsInput, optFns ...func(*aws_rds.Options)) (*aws_rds.DescribeDBEngineVersionsOutput, error)
 	DescribeDBMajorEngineVersions(ctx context.Context, params *aws_rds.DescribeDBMajorEngineVersionsInput, optFns ...func(*aws_rds.Options)) (*aws_rds.DescribeDBMajorEngineVersionsOutput, error)
+	DescribeDBProxies(ctx context.Context, params *aws_rds.DescribeDBProxiesInput, optFns ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxiesOutput, error)
+	DescribeDBProxyTargetGroups(ctx context.Context, params *aws_rds.DescribeDBProxyTargetGroupsInput, optFns ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxyTargetGroupsOutput, error)
+	DescribeDBProxyTargets(ctx context.Context, params *aws_rds.DescribeDBProxyTargetsInput, optFns ...func(*aws_rds.Options)) (*aws_rds.DescribeDBProxyTargetsOutput, error)
 }
 
 func NewFetcher(ctx context.Context, client RDSClient, tagClient resourcegroupstaggingapi.GetResourcesAPIClient, logger slog.Logger, configuration Configuration) RDSFetcher {
@@ -271,25 +276,20 @@ func NewFetcher(ctx context.Context, client RDSClient, tagClient resourcegroupst
 type RDSFetcher struct {
 	ctx           context.Context
 	client        RDSClient
-	statistics    Statistics
 	configuration Configuration
 	tagClient     resourcegroupstaggingapi.GetResourcesAPIClient
 	logger        slog.Logger
 }
 
-func (r *RDSFetcher) GetStatistics() Statistics {
-	return r.statistics
-}
-
 func (r *RDSFetcher) getPendingMaintenances(ctx context.Context) (map[string]string, error) {
-	_, span := tracer.Start(ctx, "collect-pending-maintenances")
+	ctx, span := tracer.Start(ctx, "collect-pending-maintenances")
 	defer span.End()
 
 	instances := make(map[string]string)
 
 	inputMaintenance := &aws_rds.DescribePendingMaintenanceActionsInput{}
 
-	maintenances, err := r.client.DescribePendingMaintenanceActions(context.TODO(), inputMaintenance)
+	maintenances, err := r.client.DescribePendingMaintenanceActions(ctx, inputMaintenance)
 	if err != nil {
 		span.SetStatus(codes.Error, "failed to get maintenances")
 		span.RecordError(err)
@@ -297,8 +297,6 @@ func (r *RDSFetcher) getPendingMaintenances(ctx context.Context) (map[string]str
 		return nil, fmt.Errorf("can't describe pending maintenance actions: %w", err)
 	}
 
-	r.statistics.RdsAPICall++
-
 	if maintenances == nil {
 		return nil, nil
 	}
@@ -334,12 +332,10 @@ func (r *RDSFetcher) getClusters(ctx context.Context, filters []aws_rds_types.Fi
 
 	paginatorCluster := aws_rds.NewDescribeDBClustersPaginator(r.client, inputCluster)
 	for paginatorCluster.HasMorePages() {
-		_, span := tracer.Start(ctx, "describe-rds-clusters")
+		pageCtx, span := tracer.Start(ctx, "describe-rds-clusters")
 		defer span.End()
 
-		r.statistics.RdsAPICall++
-
-		output, err := paginatorCluster.NextPage(context.TODO())
+		output, err := paginatorCluster.NextPage(pageCtx)
 		if err != nil {
 			span.SetStatus(codes.Error, "can't describe RDS clusters")
 			span.RecordError(err)
@@ -443,9 +439,7 @@ func (r *RDSFetcher) GetInstancesMetrics() (Metrics, error) {
 		instanceCtx, instanceSpan := tracer.Start(ctx, "collect-rds-instances")
 		defer instanceSpan.End()
 
-		r.statistics.RdsAPICall++
-
-		output, err := paginator.NextPage(context.TODO())
+		output, err := paginator.NextPage(instanceCtx)
 		if err != nil {
 			span.SetStatus(codes.Error, "can't get RDS instances")
 			span.RecordError(err)
@@ -453,10 +447,12 @@ func (r *RDSFetcher) GetInstancesMetrics() (Metrics, error) {
 			return Metrics{}, fmt.Errorf("can't get RDS instances: %w", err)
 		}
 
+		collectLogs := r.isLogsSizeBudgetAvailable(output.DBInstances)
+
 		for _, dbInstance := range output.DBInstances {
 			dbIdentifier := dbInstance.DBInstanceIdentifier
 
-			instanceMetrics, err := r.computeInstanceMetrics(instanceCtx, dbInstance, instanceMaintenances, &clusterMetrics)
+			instanceMetrics, err := r.computeInstanceMetrics(instanceCtx, dbInstance, instanceMaintenances, &clusterMetrics, collectLogs)
 			if err != nil {
 				span.SetStatus(codes.Error, "can't compute instance metrics")
 				span.RecordError(err)
@@ -503,8 +499,6 @@ func (r *RDSFetcher) getDBInstanceFilters(ctx context.Context) ([]aws_rds_types.
 	resourcesPaginator := resourcegroupstaggingapi.NewGetResourcesPaginator(r.tagClient, resourcesInput)
 
 	for resourcesPaginator.HasMorePages() {
-		r.statistics.TagAPICall++
-
 		resources, err := resourcesPaginator.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("can't find instances for tags %v: %w", r.configuration.TagSelections, err)
@@ -576,8 +570,6 @@ func (r *RDSFetcher) getDBClusterFilters(ctx context.Context) ([]aws_rds_types.F
 	resourcesPaginator := resourcegroupstaggingapi.NewGetResourcesPaginator(r.tagClient, resourcesInput)
 
 	for resourcesPaginator.HasMorePages() {
-		r.statistics.TagAPICall++
-
 		resources, err := resourcesPaginator.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("can't find clusters for tags %v: %w", r.configuration.TagSelections, err)
@@ -621,8 +613,33 @@ func (r *RDSFetcher) getDBClusterFilters(ctx context.Context) ([]aws_rds_types.F
 	return filters, nil
 }
 
+// shouldCollectLogs returns true if logs size of the instance must be collected
+func (r *RDSFetcher) shouldCollectLogs(dbInstance aws_rds_types.DBInstance) bool {
+	isServerless := aws.ToString(dbInstance.DBInstanceClass) == ServerlessClassType
+
+	return (r.configuration.CollectLogsSize && !isServerless) || (r.configuration.CollectServerlessLogsSize && isServerless)
+}
+
+// isLogsSizeBudgetAvailable returns true if the AWS API budget allows to fetch logs size of instances, one call per instance
+func (r *RDSFetcher) isLogsSizeBudgetAvailable(instances []aws_rds_types.DBInstance) bool {
+	if r.configuration.LogsSizeBudget == nil {
+		return true
+	}
+
+	calls := 0
+
+	for _, dbInstance := range instances {
+		if r.shouldCollectLogs(dbInstance) {
+			calls++
+		}
+	}
+
+	return calls == 0 || r.configuration.LogsSizeBudget(calls)
+}
+
 // computeInstanceMetrics returns metrics about the specified instance
-func (r *RDSFetcher) computeInstanceMetrics(ctx context.Context, dbInstance aws_rds_types.DBInstance, instanceMaintenances map[string]string, clusterMetrics *map[string]ClusterMetrics) (RdsInstanceMetrics, error) {
+// Logs size is only fetched when collectLogs is true
+func (r *RDSFetcher) computeInstanceMetrics(ctx context.Context, dbInstance aws_rds_types.DBInstance, instanceMaintenances map[string]string, clusterMetrics *map[string]ClusterMetrics, collectLogs bool) (RdsInstanceMetrics, error) {
 	dbIdentifier := dbInstance.DBInstanceIdentifier
 
 	var iops int64
@@ -667,10 +684,7 @@ func (r *RDSFetcher) computeInstanceMetrics(ctx context.Context, dbInstance aws_
 
 	var logFilesSize *int64
 
-	isServerless := *dbInstance.DBInstanceClass == ServerlessClassType
-
-	shouldCollectLogs := (r.configuration.CollectLogsSize && !isServerless) || (r.configuration.CollectServerlessLogsSize && isServerless)
-	if shouldCollectLogs {
+	if collectLogs && r.shouldCollectLogs(dbInstance) {
 		var err error
 
 		logFilesSize, err = r.getLogFilesSize(ctx, *dbIdentifier)
@@ -722,6 +736,7 @@ func (r *RDSFetcher) computeInstanceMetrics(ctx context.Context, dbInstance aws_
 		LogFilesSize:               logFilesSize,
 		MaxAllocatedStorage:        converter.GigaBytesToBytes(maxAllocatedStorage),
 		MaxIops:                    iops,
+		MonitoringInterval:         aws.ToInt32(dbInstance.MonitoringInterval),
 		MultiAZ:                    aws.ToBool(dbInstance.MultiAZ),
 		PendingMaintenanceAction:   pendingMaintenanceAction,
 		PendingModifiedValues:      pendingModifiedValues,
@@ -743,7 +758,7 @@ func (r *RDSFetcher) computeInstanceMetrics(ctx context.Context, dbInstance aws_
 
 // getLogFilesSize returns the size of all logs on the specified instance
 func (r *RDSFetcher) getLogFilesSize(ctx context.Context, dbidentifier string) (*int64, error) {
-	_, span := tracer.Start(ctx, "collect-instance-log")
+	ctx, span := tracer.Start(ctx, "collect-instance-log")
 	defer span.End()
 
 	span.SetAttributes(semconv.DBInstanceID(dbidentifier))
@@ -752,9 +767,7 @@ func (r *RDSFetcher) getLogFilesSize(ctx context.Context, dbidentifier string) (
 
 	input := &aws_rds.DescribeDBLogFilesInput{DBInstanceIdentifier: &dbidentifier}
 
-	r.statistics.RdsAPICall++
-
-	result, err := r.client.DescribeDBLogFiles(context.TODO(), input)
+	result, err := r.client.DescribeDBLogFiles(ctx, input)
 	if err != nil {
 		span.SetStatus(codes.Error, "can't describe db logs files")
 		span.RecordError(err)
@@ -48,6 +48,7 @@ func TestGetMetrics(t *testing.T) {
 	assert.Equal(t, *rdsInstance.Engine, m.Engine, "Engine mismatch")
 	assert.Equal(t, *rdsInstance.EngineVersion, m.EngineVersion, "Engine version mismatch")
 	assert.Equal(t, *rdsInstance.PerformanceInsightsEnabled, m.PerformanceInsightsEnabled, "PerformanceInsights enabled mismatch")
+	assert.Equal(t, *rdsInstance.MonitoringInterval, m.MonitoringInterval, "Monitoring interval mismatch")
 	assert.Equal(t, *rdsInstance.PubliclyAccessible, m.PubliclyAccessible, "PubliclyAccessible mismatch")
 	assert.Equal(t, *rdsInstance.DbiResourceId, m.DbiResourceID, "DbiResourceId mismatch")
 	assert.Equal(t, *rdsInstance.DBInstanceClass, m.DBInstanceClass, "DBInstanceIdentifier mismatch")
@@ -3,7 +3,10 @@ package mocks
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_servicequotas "github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	aws_servicequotas_types "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/servicequotas"
@@ -15,51 +18,122 @@ const (
 	DBinstancesQuota          = float64(10)
 	TotalStorage              = float64(10)
 	ManualDBInstanceSnapshots = float64(42)
+	DefaultQuota              = float64(5) // AWS default value of all quotas
 )
 
+var ErrListServiceQuotas = errors.New("ListServiceQuotas failed")
+
+// appliedValue returns the applied value of RDS quotas
+func appliedValue(quotaCode string) float64 {
+	switch quotaCode {
+	case servicequotas.DBinstancesQuotacode:
+		return DBinstancesQuota
+	case servicequotas.TotalStorageQuotaCode:
+		return TotalStorage
+	case servicequotas.ManualDBInstanceSnapshotsQuotaCode:
+		return ManualDBInstanceSnapshots
+	default:
+		return UnknownServiceQuota
+	}
+}
+
+// NewServiceQuota returns an adjustable RDS quota
+func NewServiceQuota(quotaCode string, value float64) aws_servicequotas_types.ServiceQuota {
+	return aws_servicequotas_types.ServiceQuota{
+		ServiceCode: aws.String(servicequotas.RDSServiceCode),
+		QuotaCode:   aws.String(quotaCode),
+		QuotaName:   aws.String("Quota " + quotaCode),
+		Adjustable:  true,
+		Value:       aws.Float64(value),
+	}
+}
+
+// newServiceQuotas returns quotas of all AWS/Usage resources
+func newServiceQuotas(value func(quotaCode string) float64) []aws_servicequotas_types.ServiceQuota {
+	quotas := make([]aws_servicequotas_types.ServiceQuota, 0, len(servicequotas.UsageQuotas))
+	for _, quota := range servicequotas.UsageQuotas {
+		quotas = append(quotas, NewServiceQuota(quota.QuotaCode, value(quota.QuotaCode)))
+	}
+
+	return quotas
+}
+
+// ServiceQuotasClient returns applied and default values of quotas of all AWS/Usage resources
 type ServiceQuotasClient struct{}
 
-func (m ServiceQuotasClient) GetServiceQuota(context context.Context, input *aws_servicequotas.GetServiceQuotaInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.GetServiceQuotaOutput, error) {
-	value := UnknownServiceQuota
-
-	if *input.ServiceCode == servicequotas.RDSServiceCode {
-		switch *input.QuotaCode {
-		case servicequotas.DBinstancesQuotacode:
-			value = DBinstancesQuota
-		case servicequotas.TotalStorageQuotaCode:
-			value = TotalStorage
-		case servicequotas.ManualDBInstanceSnapshotsQuotaCode:
-			value = ManualDBInstanceSnapshots
-		}
+func (m ServiceQuotasClient) ListServiceQuotas(ctx context.Context, input *aws_servicequotas.ListServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error) {
+	return &aws_servicequotas.ListServiceQuotasOutput{Quotas: newServiceQuotas(appliedValue)}, nil
+}
+
+func (m ServiceQuotasClient) ListAWSDefaultServiceQuotas(ctx context.Context, input *aws_servicequotas.ListAWSDefaultServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListAWSDefaultServiceQuotasOutput, error) {
+	return &aws_servicequotas.ListAWSDefaultServiceQuotasOutput{Quotas: newServiceQuotas(func(string) float64 { return DefaultQuota })}, nil
+}
+
+// ServiceQuotasClientPaginated returns applied and default values of quotas of all AWS/Usage resources, one quota per page
+type ServiceQuotasClientPaginated struct{}
+
+// getPage returns the quota of the page and the token of the next page
+func getPage(quotas []aws_servicequotas_types.ServiceQuota, token *string) ([]aws_servicequotas_types.ServiceQuota, *string) {
+	index, _ := strconv.Atoi(aws.ToString(token))
+
+	var nextToken *string
+	if index+1 < len(quotas) {
+		nextToken = aws.String(strconv.Itoa(index + 1))
 	}
 
-	quota := &aws_servicequotas_types.ServiceQuota{Value: &value}
+	return quotas[index : index+1], nextToken
+}
+
+func (m ServiceQuotasClientPaginated) ListServiceQuotas(ctx context.Context, input *aws_servicequotas.ListServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error) {
+	quotas, nextToken := getPage(newServiceQuotas(appliedValue), input.NextToken)
+
+	return &aws_servicequotas.ListServiceQuotasOutput{Quotas: quotas, NextToken: nextToken}, nil
+}
+
+func (m ServiceQuotasClientPaginated) ListAWSDefaultServiceQuotas(ctx context.Context, input *aws_servicequotas.ListAWSDefaultServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListAWSDefaultServiceQuotasOutput, error) {
+	quotas, nextToken := getPage(newServiceQuotas(func(string) float64 { return DefaultQuota }), input.NextToken)
 
-	return &aws_servicequotas.GetServiceQuotaOutput{Quota: quota}, nil
+	return &aws_servicequotas.ListAWSDefaultServiceQuotasOutput{Quotas: quotas, NextToken: nextToken}, nil
 }
 
+// ServiceQuotasClientQuotaError returns the quota with an error reason in the applied quotas
 type ServiceQuotasClientQuotaError struct {
-	ExpectedErrorQotaCode    string
-	ExpectedErrorQuotaOutput *aws_servicequotas.GetServiceQuotaOutput
+	ServiceQuotasClient
+	ExpectedErrorQotaCode string
+	ExpectedErrorReason   *aws_servicequotas_types.ErrorReason
 }
 
-func (m ServiceQuotasClientQuotaError) GetServiceQuota(context context.Context, input *aws_servicequotas.GetServiceQuotaInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.GetServiceQuotaOutput, error) {
-	value := UnknownServiceQuota
-
-	if *input.ServiceCode == servicequotas.RDSServiceCode {
-		switch *input.QuotaCode {
-		case m.ExpectedErrorQotaCode:
-			return m.ExpectedErrorQuotaOutput, nil
-		case servicequotas.DBinstancesQuotacode:
-			value = DBinstancesQuota
-		case servicequotas.TotalStorageQuotaCode:
-			value = TotalStorage
-		case servicequotas.ManualDBInstanceSnapshotsQuotaCode:
-			value = ManualDBInstanceSnapshots
+func (m ServiceQuotasClientQuotaError) ListServiceQuotas(ctx context.Context, input *aws_servicequotas.ListServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error) {
+	quotas := newServiceQuotas(appliedValue)
+	for i, quota := range quotas {
+		if aws.ToString(quota.QuotaCode) == m.ExpectedErrorQotaCode {
+			quotas[i] = aws_servicequotas_types.ServiceQuota{QuotaCode: quota.QuotaCode, ErrorReason: m.ExpectedErrorReason}
 		}
 	}
 
-	quota := &aws_servicequotas_types.ServiceQuota{Value: &value}
+	return &aws_servicequotas.ListServiceQuotasOutput{Quotas: quotas}, nil
+}
+
+// ServiceQuotasClientListError fails to list applied quotas and returns default quotas
+type ServiceQuotasClientListError struct {
+	ServiceQuotasClient
+}
+
+func (m ServiceQuotasClientListError) ListServiceQuotas(ctx context.Context, input *aws_servicequotas.ListServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error) {
+	return nil, ErrListServiceQuotas
+}
+
+// ServiceQuotasClientTimeout simulates an unresponsive AWS API that only returns when the request is cancelled
+type ServiceQuotasClientTimeout struct{}
+
+func (m ServiceQuotasClientTimeout) ListServiceQuotas(ctx context.Context, input *aws_servicequotas.ListServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (m ServiceQuotasClientTimeout) ListAWSDefaultServiceQuotas(ctx context.Context, input *aws_servicequotas.ListAWSDefaultServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListAWSDefaultServiceQuotasOutput, error) {
+	<-ctx.Done()
 
-	return &aws_servicequotas.GetServiceQuotaOutput{Quota: quota}, nil
+	return nil, ctx.Err()
 }
@@ -9,17 +9,14 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_servicequotas "github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	aws_servicequotas_types "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/trace"
 	converter "github.com/qonto/prometheus-rds-exporter/internal/app/unit"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 )
 
-var (
-	errNoQuota    = errors.New("no AWS quota with this code")
-	errQuotaError = errors.New("AWS return error for this quota")
-	tracer        = otel.Tracer("github/qonto/prometheus-rds-exporter/internal/app/servicequotas")
-)
+var tracer = otel.Tracer("github/qonto/prometheus-rds-exporter/internal/app/servicequotas")
 
 const (
 	RDSServiceCode = "rds" // AWS RDS service code in AWS quotas API
@@ -28,21 +25,81 @@ const (
 	DBinstancesQuotacode               = "L-7B6409FD" // DB instances
 	TotalStorageQuotaCode              = "L-7ADDB58A" // Total storage for all DB instances
 	ManualDBInstanceSnapshotsQuotaCode = "L-272F1212" // Manual DB instance snapshots
+	DBClustersQuotaCode                = "L-952B80B8" // DB clusters
+	DBClusterParameterGroupsQuotaCode  = "L-E4F3C5A8" // DB cluster parameter groups
+	DBParameterGroupsQuotaCode         = "L-DE55804A" // DB parameter groups
+	DBSecurityGroupsQuotaCode          = "L-732153D0" // DB security groups
+	DBSubnetGroupsQuotaCode            = "L-48C6BF81" // DB subnet groups
+	EventSubscriptionsQuotaCode        = "L-A59F4C87" // Event subscriptions
+	ManualDBClusterSnapshotsQuotaCode  = "L-9B510759" // Manual DB cluster snapshots
+	OptionGroupsQuotaCode              = "L-9FA33840" // Option groups
+	ReadReplicasPerPrimaryQuotaCode    = "L-5BC124EF" // Read replicas per primary
+	ReservedDBInstancesQuotaCode       = "L-78E853F4" // Reserved DB instances
 )
 
+// UsageQuota pairs a resource of the AWS/Usage namespace with its RDS service quota
+type UsageQuota struct {
+	Resource  string // Resource dimension of the AWS/Usage ResourceCount metric
+	QuotaCode string
+}
+
+// UsageQuotas are the quotas of RDS resources published in the AWS/Usage namespace
+var UsageQuotas = []UsageQuota{
+	{Resource: "AllocatedStorage", QuotaCode: TotalStorageQuotaCode},
+	{Resource: "DBClusterParameterGroups", QuotaCode: DBClusterParameterGroupsQuotaCode},
+	{Resource: "DBClusters", QuotaCode: DBClustersQuotaCode},
+	{Resource: "DBInstances", QuotaCode: DBinstancesQuotacode},
+	{Resource: "DBParameterGroups", QuotaCode: DBParameterGroupsQuotaCode},
+	{Resource: "DBSecurityGroups", QuotaCode: DBSecurityGroupsQuotaCode},
+	{Resource: "DBSubnetGroups", QuotaCode: DBSubnetGroupsQuotaCode},
+	{Resource: "EventSubscriptions", QuotaCode: EventSubscriptionsQuotaCode},
+	{Resource: "ManualClusterSnapshots", QuotaCode: ManualDBClusterSnapshotsQuotaCode},
+	{Resource: "ManualSnapshots", QuotaCode: ManualDBInstanceSnapshotsQuotaCode},
+	{Resource: "OptionGroups", QuotaCode: OptionGroupsQuotaCode},
+	{Resource: "ReadReplicasPerMaster", QuotaCode: ReadReplicasPerPrimaryQuotaCode},
+	{Resource: "ReservedDBInstances", QuotaCode: ReservedDBInstancesQuotaCode},
+}
+
+// Quota is a quota of the AWS RDS service
+type Quota struct {
+	Code         string
+	Name         string
+	Adjustable   bool
+	Value        *float64 // Value applied to the AWS account, nil when not returned by ListServiceQuotas
+	DefaultValue *float64 // AWS default value, nil when not returned by ListAWSDefaultServiceQuotas
+}
+
+// EffectiveValue returns the applied value of the quota, or its default value when no value is applied
+func (q Quota) EffectiveValue() (float64, bool) {
+	if q.Value != nil {
+		return *q.Value, true
+	}
+
+	if q.DefaultValue != nil {
+		return *q.DefaultValue, true
+	}
+
+	return 0, false
+}
+
 // Metrics contains the quotas to be monitored for the AWS RDS service
 type Metrics struct {
 	DBinstances               float64
 	TotalStorage              float64
 	ManualDBInstanceSnapshots float64
+	Resources                 map[string]float64 // AWS/Usage resource => quota, total storage is in bytes
+	Quotas                    map[string]Quota   // Quota code => quota
 }
 
+// Statistics of the fetch. AWS API calls are recorded by the AWS SDK middleware,
+// UsageAPICall is only kept to estimate the AWS API budget of the next fetch
 type Statistics struct {
-	UsageAPICall float64
+	UsageAPICall float64 // Listed pages of applied and default quotas
 }
 
 type ServiceQuotasClient interface {
-	GetServiceQuota(ctx context.Context, input *aws_servicequotas.GetServiceQuotaInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.GetServiceQuotaOutput, error)
+	ListServiceQuotas(ctx context.Context, input *aws_servicequotas.ListServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListServiceQuotasOutput, error)
+	ListAWSDefaultServiceQuotas(ctx context.Context, input *aws_servicequotas.ListAWSDefaultServiceQuotasInput, optFns ...func(*aws_servicequotas.Options)) (*aws_servicequotas.ListAWSDefaultServiceQuotasOutput, error)
 }
 
 func NewFetcher(ctx context.Context, client ServiceQuotasClient, logger slog.Logger) *serviceQuotaFetcher {
@@ -64,69 +121,133 @@ func (s *serviceQuotaFetcher) GetStatistics() Statistics {
 	return s.statistics
 }
 
-// GetQuota retrieves and returns the AWS quota value for the specified serviceCode and quotaCode
-func (s *serviceQuotaFetcher) getQuota(serviceCode string, quotaCode string) (float64, error) {
-	_, span := tracer.Start(s.ctx, "get-quota")
-	defer span.End()
+// listQuotas returns quotas of the service with their value applied to the AWS account
+func (s *serviceQuotaFetcher) listQuotas(ctx context.Context, serviceCode string) ([]aws_servicequotas_types.ServiceQuota, error) {
+	var quotas []aws_servicequotas_types.ServiceQuota
+
+	paginator := aws_servicequotas.NewListServiceQuotasPaginator(s.client, &aws_servicequotas.ListServiceQuotasInput{ServiceCode: &serviceCode})
+	for paginator.HasMorePages() {
+		s.statistics.UsageAPICall++
 
-	span.SetAttributes(trace.AWSQuotaServiceCode(serviceCode), trace.AWSQuotaCode(quotaCode))
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return quotas, fmt.Errorf("can't list %s service quotas: %w", serviceCode, err)
+		}
 
-	params := &aws_servicequotas.GetServiceQuotaInput{
-		ServiceCode: &serviceCode,
-		QuotaCode:   &quotaCode,
+		quotas = append(quotas, output.Quotas...)
 	}
 
-	s.statistics.UsageAPICall++
+	return quotas, nil
+}
 
-	result, err := s.client.GetServiceQuota(s.ctx, params)
-	if err != nil {
-		span.SetStatus(codes.Error, "failed to get quota")
-		span.RecordError(err)
+// listDefaultQuotas returns AWS default quotas of the service
+func (s *serviceQuotaFetcher) listDefaultQuotas(ctx context.Context, serviceCode string) ([]aws_servicequotas_types.ServiceQuota, error) {
+	var quotas []aws_servicequotas_types.ServiceQuota
 
-		return 0, fmt.Errorf("can't get %s/%s service quota: %w", serviceCode, quotaCode, err)
-	}
+	paginator := aws_servicequotas.NewListAWSDefaultServiceQuotasPaginator(s.client, &aws_servicequotas.ListAWSDefaultServiceQuotasInput{ServiceCode: &serviceCode})
+	for paginator.HasMorePages() {
+		s.statistics.UsageAPICall++
 
-	// AWS response payload could contains errors (eg. missing permission)
-	if result.Quota.ErrorReason != nil {
-		s.logger.Error("AWS quota error: ", "errorCode", result.Quota.ErrorReason.ErrorCode, "message", aws.ToString(result.Quota.ErrorReason.ErrorMessage))
-		span.SetStatus(codes.Error, "failed to fetch quota")
-		span.RecordError(errQuotaError)
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return quotas, fmt.Errorf("can't list %s AWS default service quotas: %w", serviceCode, err)
+		}
 
-		return 0, errQuotaError
+		quotas = append(quotas, output.Quotas...)
 	}
 
-	if result.Quota == nil {
-		span.SetStatus(codes.Error, "no quota")
-		span.RecordError(errQuotaError)
+	return quotas, nil
+}
 
-		return 0, fmt.Errorf("no quota for %s/%s: %w", serviceCode, quotaCode, errNoQuota)
-	}
+// addQuotas merges listed quotas into the catalog, setValue sets their applied or default value
+// Quotas returned with an error (eg. missing permission) are skipped
+func (s *serviceQuotaFetcher) addQuotas(catalog map[string]Quota, quotas []aws_servicequotas_types.ServiceQuota, setValue func(*Quota, *float64)) {
+	for _, serviceQuota := range quotas {
+		code := aws.ToString(serviceQuota.QuotaCode)
+
+		if serviceQuota.ErrorReason != nil {
+			s.logger.Warn("AWS quota error", "quotaCode", code, "errorCode", serviceQuota.ErrorReason.ErrorCode, "message", aws.ToString(serviceQuota.ErrorReason.ErrorMessage))
+
+			continue
+		}
 
-	span.SetStatus(codes.Ok, "quota fetched")
+		if code == "" || serviceQuota.Value == nil {
+			continue
+		}
 
-	return *result.Quota.Value, nil
+		quota, found := catalog[code]
+		if !found {
+			quota = Quota{Code: code}
+		}
+
+		if name := aws.ToString(serviceQuota.QuotaName); name != "" {
+			quota.Name = name
+		}
+
+		quota.Adjustable = quota.Adjustable || serviceQuota.Adjustable
+		setValue(&quota, serviceQuota.Value)
+
+		catalog[code] = quota
+	}
 }
 
 // GetRDSQuotas retrieves quotas for the AWS RDS service
+// Quotas are listed with their applied and AWS default values. A failing list doesn't prevent reporting quotas of the other, errors are returned with fetched quotas
 func (s *serviceQuotaFetcher) GetRDSQuotas() (Metrics, error) {
-	DBinstances, err := s.getQuota(RDSServiceCode, DBinstancesQuotacode)
+	ctx, span := tracer.Start(s.ctx, "get-quotas")
+	defer span.End()
+
+	span.SetAttributes(trace.AWSQuotaServiceCode(RDSServiceCode))
+
+	catalog := make(map[string]Quota)
+
+	var errs []error
+
+	appliedQuotas, err := s.listQuotas(ctx, RDSServiceCode)
 	if err != nil {
-		return Metrics{}, fmt.Errorf("can't fetch DBinstance quota: %w", err)
+		errs = append(errs, err)
 	}
 
-	totalStorage, err := s.getQuota(RDSServiceCode, TotalStorageQuotaCode)
+	defaultQuotas, err := s.listDefaultQuotas(ctx, RDSServiceCode)
 	if err != nil {
-		return Metrics{}, fmt.Errorf("can't fetch total storage quota: %w", err)
+		errs = append(errs, err)
 	}
 
-	manualDBInstanceSnapshots, err := s.getQuota(RDSServiceCode, ManualDBInstanceSnapshotsQuotaCode)
-	if err != nil {
-		return Metrics{}, fmt.Errorf("can't fetch manual db instance snapshots quota: %w", err)
+	s.addQuotas(catalog, appliedQuotas, func(quota *Quota, value *float64) { quota.Value = value })
+	s.addQuotas(catalog, defaultQuotas, func(quota *Quota, value *float64) { quota.DefaultValue = value })
+
+	metrics := Metrics{
+		Resources: make(map[string]float64, len(UsageQuotas)),
+		Quotas:    catalog,
 	}
 
-	return Metrics{
-		DBinstances:               DBinstances,
-		TotalStorage:              converter.GigaBytesToBytes(totalStorage),
-		ManualDBInstanceSnapshots: manualDBInstanceSnapshots,
-	}, nil
+	for _, usageQuota := range UsageQuotas {
+		value, found := catalog[usageQuota.QuotaCode].EffectiveValue()
+		if !found {
+			continue
+		}
+
+		if usageQuota.QuotaCode == TotalStorageQuotaCode {
+			value = converter.GigaBytesToBytes(value)
+		}
+
+		metrics.Resources[usageQuota.Resource] = value
+	}
+
+	metrics.DBinstances = metrics.Resources["DBInstances"]
+	metrics.TotalStorage = metrics.Resources["AllocatedStorage"]
+	metrics.ManualDBInstanceSnapshots = metrics.Resources["ManualSnapshots"]
+
+	if len(errs) > 0 {
+		err = errors.Join(errs...)
+
+		span.SetStatus(codes.Error, "can't list quotas")
+		span.RecordError(err)
+
+		return metrics, err
+	}
+
+	span.SetStatus(codes.Ok, "quotas fetched")
+
+	return metrics, nil
 }
@@ -5,7 +5,6 @@ import (
 	"log/slog"
 	"testing"
 
-	aws_servicequotas "github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	"github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
 	"github.com/qonto/prometheus-rds-exporter/internal/app/servicequotas"
 	mock "github.com/qonto/prometheus-rds-exporter/internal/app/servicequotas/mock"
@@ -22,27 +21,53 @@ func TestGetRDSQuotas(t *testing.T) {
 		context := context.TODO()
 		client := mock.ServiceQuotasClient{}
 
-		result, err := servicequotas.NewFetcher(context, client, *logger).GetRDSQuotas()
+		fetcher := servicequotas.NewFetcher(context, client, *logger)
+
+		result, err := fetcher.GetRDSQuotas()
 		require.NoError(t, err, "GetRDSQuotas must succeed")
 		assert.Equal(t, mock.DBinstancesQuota, result.DBinstances, "DbInstance quota is incorrect")
 		assert.Equal(t, converter.GigaBytesToBytes(mock.TotalStorage), result.TotalStorage, "Total storage quota is incorrect")
 		assert.Equal(t, mock.ManualDBInstanceSnapshots, result.ManualDBInstanceSnapshots, "Manual db instance snapshot quota is incorrect")
+
+		assert.Len(t, result.Resources, len(servicequotas.UsageQuotas), "Quota of every AWS/Usage resource must be returned")
+		assert.Equal(t, converter.GigaBytesToBytes(mock.TotalStorage), result.Resources["AllocatedStorage"], "Allocated storage quota must be in bytes")
+		assert.Equal(t, mock.UnknownServiceQuota, result.Resources["DBClusters"], "DB clusters quota is incorrect")
+
+		quota := result.Quotas[servicequotas.DBinstancesQuotacode]
+		assert.Equal(t, "Quota "+servicequotas.DBinstancesQuotacode, quota.Name, "Quota name is incorrect")
+		assert.True(t, quota.Adjustable, "Quota must be adjustable")
+		assert.Equal(t, mock.DBinstancesQuota, *quota.Value, "Applied value is incorrect")
+		assert.Equal(t, mock.DefaultQuota, *quota.DefaultValue, "Default value is incorrect")
+
+		assert.Equal(t, float64(2), fetcher.GetStatistics().UsageAPICall, "One call to list applied and default quotas")
 	})
 
-	t.Run("GetRDSQuotasErrorFetchingQuotaNilErrorMessage", func(t *testing.T) {
+	t.Run("GetRDSQuotasErrorFetchingQuota", func(t *testing.T) {
 		context := context.TODO()
 		client := mock.ServiceQuotasClientQuotaError{
-			ExpectedErrorQotaCode: servicequotas.DBinstancesQuotacode,
-			ExpectedErrorQuotaOutput: &aws_servicequotas.GetServiceQuotaOutput{
-				Quota: &types.ServiceQuota{
-					ErrorReason: &types.ErrorReason{
-						ErrorCode: types.ErrorCodeServiceQuotaNotAvailableError,
-					},
-				},
+			ExpectedErrorQotaCode: servicequotas.DBSecurityGroupsQuotaCode,
+			ExpectedErrorReason: &types.ErrorReason{
+				ErrorCode: types.ErrorCodeServiceQuotaNotAvailableError,
 			},
 		}
 
-		_, err := servicequotas.NewFetcher(context, client, *logger).GetRDSQuotas()
-		require.EqualError(t, err, "can't fetch DBinstance quota: AWS return error for this quota")
+		result, err := servicequotas.NewFetcher(context, client, *logger).GetRDSQuotas()
+		require.NoError(t, err, "Quota errors must not fail GetRDSQuotas")
+
+		quota := result.Quotas[servicequotas.DBSecurityGroupsQuotaCode]
+		assert.Nil(t, quota.Value, "Applied value of failing quota must be skipped")
+		assert.Equal(t, mock.DefaultQuota, *quota.DefaultValue, "Default value of failing quota must be kept")
+		assert.Equal(t, mock.DefaultQuota, result.Resources["DBSecurityGroups"], "Resource quota must fall back to the default value")
+		assert.Equal(t, mock.UnknownServiceQuota, result.Resources["DBClusters"], "Other resource quotas must be returned")
+	})
+
+	t.Run("GetRDSQuotasErrorListingQuotas", func(t *testing.T) {
+		context := context.TODO()
+		client := mock.ServiceQuotasClientListError{}
+
+		result, err := servicequotas.NewFetcher(context, client, *logger).GetRDSQuotas()
+		require.ErrorIs(t, err, mock.ErrListServiceQuotas, "GetRDSQuotas must return list errors")
+		assert.Equal(t, mock.DefaultQuota, result.DBinstances, "Default quotas must be returned")
+		assert.Len(t, result.Quotas, len(servicequotas.UsageQuotas), "Default quotas must be returned")
 	})
 }
@@ -13,6 +13,8 @@ const (
 	AWSServiceCodeOtelKey    = attribute.Key("qonto.prometheus_rds_exporter.aws.quota.service_code")
 	AWSQuotaCodeOtelKey      = attribute.Key("qonto.prometheus_rds_exporter.aws.quota.code")
 	AWSInstanceTypesCountKey = attribute.Key("qonto.prometheus_rds_exporter.aws.instance-types-count")
+	AWSAccountIDOtelKey      = attribute.Key("qonto.prometheus_rds_exporter.aws.account_id")
+	AWSRegionOtelKey         = attribute.Key("qonto.prometheus_rds_exporter.aws.region")
 )
 
 func AWSQuotaServiceCode(val string) attribute.KeyValue {
@@ -26,3 +28,11 @@ func AWSQuotaCode(val string) attribute.KeyValue {
 func AWSInstanceTypesCount(val int64) attribute.KeyValue {
 	return AWSInstanceTypesCountKey.Int64(val)
 }
+
+func AWSAccountID(val string) attribute.KeyValue {
+	return AWSAccountIDOtelKey.String(val)
+}
+
+func AWSRegion(val string) attribute.KeyValue {
+	return AWSRegionOtelKey.String(val)
+}
@@ -24,6 +24,10 @@ func MegaBytesToBytes[N Number](size N) N {
 	return size * unit * unit
 }
 
+func KiloBytesToBytes[N Number](size N) N {
+	return size * unit
+}
+
 func KiloByteToMegaBytes[N Number](size N) N {
 	return size / unit
 }
@@ -31,3 +35,12 @@ func KiloByteToMegaBytes[N Number](size N) N {
 func DaystoSeconds[N Number](days N) N {
 	return days * secondsPerDay
 }
+
+// BoolToFloat64 converts a boolean to 1 or 0 for Prometheus gauges
+func BoolToFloat64(value bool) float64 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
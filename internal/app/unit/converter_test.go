@@ -18,6 +18,11 @@ func TestMegaBytesToBytes(t *testing.T) {
 	assert.Equal(t, float64(1048576), converter.MegaBytesToBytes(float64(1)), "1 MB conversion is not correct")
 }
 
+func TestKiloBytesToBytes(t *testing.T) {
+	assert.Equal(t, int64(1024), converter.KiloBytesToBytes(int64(1)), "1 KB conversion is not correct")
+	assert.Equal(t, float64(1536), converter.KiloBytesToBytes(float64(1.5)), "1.5 KB conversion is not correct")
+}
+
 func TestKiloByteToMegaBytes(t *testing.T) {
 	assert.Equal(t, int64(1), converter.KiloByteToMegaBytes(int64(1024)), "1 MB conversion is not correct")
 }
@@ -26,3 +31,8 @@ func TestDaystoSeconds(t *testing.T) {
 	assert.Equal(t, int32(86400), converter.DaystoSeconds(int32(1)), "1 day conversion is not correct")
 	assert.Equal(t, int32(604800), converter.DaystoSeconds(int32(7)), "7 days conversion is not correct")
 }
+
+func TestBoolToFloat64(t *testing.T) {
+	assert.InDelta(t, float64(1), converter.BoolToFloat64(true), 0, "true conversion is not correct")
+	assert.InDelta(t, float64(0), converter.BoolToFloat64(false), 0, "false conversion is not correct")
+}
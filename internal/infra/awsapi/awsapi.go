@@ -0,0 +1,187 @@
+// Package awsapi instruments AWS SDK clients
+package awsapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// AWS services names used in metrics labels and configuration
+const (
+	RDSService                 = "rds"
+	EC2Service                 = "ec2"
+	CloudWatchService          = "cloudwatch"
+	ServiceQuotasService       = "servicequotas"
+	TagService                 = "tag"
+	OrganizationsService       = "organizations"
+	STSService                 = "sts"
+	PerformanceInsightsService = "pi"
+	CloudWatchLogsService      = "logs"
+)
+
+// Services returns names of AWS services queried by the exporter to collect metrics
+func Services() []string {
+	return []string{RDSService, EC2Service, CloudWatchService, ServiceQuotasService, TagService, OrganizationsService, PerformanceInsightsService, CloudWatchLogsService}
+}
+
+// serviceIDs maps AWS SDK service IDs to AWS services names
+var serviceIDs = map[string]string{
+	"RDS":                         RDSService,
+	"EC2":                         EC2Service,
+	"CloudWatch":                  CloudWatchService,
+	"Service Quotas":              ServiceQuotasService,
+	"Resource Groups Tagging API": TagService,
+	"Organizations":               OrganizationsService,
+	"STS":                         STSService,
+	"PI":                          PerformanceInsightsService,
+	"CloudWatch Logs":             CloudWatchLogsService,
+}
+
+// UsageAPI labels AWS API calls metrics of CloudWatch calls fetching AWS/Usage metrics, distinct from other CloudWatch calls
+const UsageAPI = "usage"
+
+type apiNameKey struct{}
+
+// WithAPIName returns a context labelling AWS API calls metrics of its calls with the API name instead of the AWS service name
+// AWS API budgets still apply to the AWS service
+func WithAPIName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, apiNameKey{}, name)
+}
+
+// getAPIName returns the API name of the AWS API call, defaults to the AWS service name
+func getAPIName(ctx context.Context) string {
+	if name, ok := ctx.Value(apiNameKey{}).(string); ok {
+		return name
+	}
+
+	return ServiceName(awsmiddleware.GetServiceID(ctx))
+}
+
+// ServiceName returns the AWS service name of an AWS SDK service ID
+func ServiceName(serviceID string) string {
+	if name, found := serviceIDs[serviceID]; found {
+		return name
+	}
+
+	return strings.ToLower(strings.ReplaceAll(serviceID, " ", ""))
+}
+
+const (
+	middlewareID          = "PrometheusRDSExporterMetrics"
+	accountIDMiddlewareID = "PrometheusRDSExporterAccountID"
+
+	// noResponseStatusCode is the status code label of AWS API calls without HTTP response (eg. network errors)
+	noResponseStatusCode = "error"
+)
+
+type accountIDKey struct{}
+
+// Metrics exposes AWS API calls metrics recorded by the AWS SDK middleware
+type Metrics struct {
+	apiCalls          *prometheus.CounterVec
+	apiCallDuration   *prometheus.HistogramVec
+	throttledRequests *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		apiCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rds_api_call_total",
+			Help: "Number of call to AWS API",
+		}, []string{"aws_account_id", "aws_region", "api", "operation", "status_code"}),
+		apiCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "rds_exporter_aws_api_call_duration_seconds",
+			Help:    "Duration of AWS API calls",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"api", "operation"}),
+		throttledRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rds_exporter_aws_throttled_requests_total",
+			Help: "Total number of AWS API requests throttled by AWS, including retried requests",
+		}, []string{"api", "operation"}),
+	}
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.apiCalls.Describe(ch)
+	m.apiCallDuration.Describe(ch)
+	m.throttledRequests.Describe(ch)
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.apiCalls.Collect(ch)
+	m.apiCallDuration.Collect(ch)
+	m.throttledRequests.Collect(ch)
+}
+
+// WithAccountID returns an AWS SDK API option labelling AWS API calls metrics with the AWS account ID
+func WithAccountID(awsAccountID string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(accountIDMiddlewareID, func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			return next.HandleInitialize(middleware.WithStackValue(ctx, accountIDKey{}, awsAccountID), in)
+		}), middleware.Before)
+	}
+}
+
+func getAccountID(ctx context.Context) string {
+	awsAccountID, _ := middleware.GetStackValue(ctx, accountIDKey{}).(string)
+
+	return awsAccountID
+}
+
+// getStatusCode returns the HTTP status code of the AWS API call
+func getStatusCode(metadata middleware.Metadata) string {
+	response, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response)
+	if !ok || response == nil {
+		return noResponseStatusCode
+	}
+
+	return strconv.Itoa(response.StatusCode)
+}
+
+// AddMiddlewares adds the metrics middleware to the AWS SDK client stack
+// The middleware runs after the retry middleware to record each attempt as an AWS API call
+func (m *Metrics) AddMiddlewares(stack *middleware.Stack) error {
+	err := stack.Finalize.Insert(m, "Retry", middleware.After)
+	if err != nil {
+		return fmt.Errorf("can't add AWS API metrics middleware: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Metrics) ID() string {
+	return middlewareID
+}
+
+func (m *Metrics) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	start := time.Now()
+
+	out, metadata, err := next.HandleFinalize(ctx, in)
+
+	api := getAPIName(ctx)
+	operation := awsmiddleware.GetOperationName(ctx)
+
+	m.apiCalls.WithLabelValues(getAccountID(ctx), awsmiddleware.GetRegion(ctx), api, operation, getStatusCode(metadata)).Inc()
+	m.apiCallDuration.WithLabelValues(api, operation).Observe(time.Since(start).Seconds())
+
+	if err != nil && IsThrottle(err) {
+		m.throttledRequests.WithLabelValues(api, operation).Inc()
+	}
+
+	return out, metadata, err
+}
+
+// IsThrottle returns true if the error is an AWS API throttling error
+func IsThrottle(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
@@ -0,0 +1,200 @@
+package awsapi_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	aws_rds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/qonto/prometheus-rds-exporter/internal/infra/awsapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// httpClient returns the same HTTP response to all requests
+type httpClient struct {
+	statusCode int
+	body       string
+}
+
+func (c httpClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: c.statusCode,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+		Request:    req,
+	}, nil
+}
+
+func newRDSClient(metrics *awsapi.Metrics, client httpClient, maxAttempts int, apiOptions ...func(*middleware.Stack) error) *aws_rds.Client {
+	return aws_rds.New(aws_rds.Options{
+		Region:      "eu-west-3",
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  client,
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = maxAttempts
+			o.RateLimiter = ratelimit.None
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		}),
+		APIOptions: append([]func(*middleware.Stack) error{metrics.AddMiddlewares}, apiOptions...),
+	})
+}
+
+func TestAPICalls(t *testing.T) {
+	metrics := awsapi.NewMetrics()
+
+	client := newRDSClient(metrics, httpClient{
+		statusCode: http.StatusOK,
+		body:       `<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances></DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`,
+	}, 3, awsapi.WithAccountID("123456789012"))
+
+	for range 2 {
+		_, err := client.DescribeDBInstances(context.TODO(), &aws_rds.DescribeDBInstancesInput{})
+		require.NoError(t, err, "DescribeDBInstances must succeed")
+	}
+
+	expected := `
+# HELP rds_api_call_total Number of call to AWS API
+# TYPE rds_api_call_total counter
+rds_api_call_total{api="rds",aws_account_id="123456789012",aws_region="eu-west-3",operation="DescribeDBInstances",status_code="200"} 2
+`
+
+	err := testutil.CollectAndCompare(metrics, strings.NewReader(expected), "rds_api_call_total")
+	require.NoError(t, err, "should count AWS API calls per operation and status code")
+
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics, "rds_exporter_aws_api_call_duration_seconds"), "should record latency of AWS API calls per operation")
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics, "rds_exporter_aws_throttled_requests_total"), "should not count successful calls as throttled")
+}
+
+func TestAPICallsWithAPIName(t *testing.T) {
+	metrics := awsapi.NewMetrics()
+
+	client := newRDSClient(metrics, httpClient{
+		statusCode: http.StatusOK,
+		body:       `<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances></DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`,
+	}, 3, awsapi.WithAccountID("123456789012"))
+
+	_, err := client.DescribeDBInstances(awsapi.WithAPIName(context.TODO(), awsapi.UsageAPI), &aws_rds.DescribeDBInstancesInput{})
+	require.NoError(t, err, "DescribeDBInstances must succeed")
+
+	expected := `
+# HELP rds_api_call_total Number of call to AWS API
+# TYPE rds_api_call_total counter
+rds_api_call_total{api="usage",aws_account_id="123456789012",aws_region="eu-west-3",operation="DescribeDBInstances",status_code="200"} 1
+`
+
+	err = testutil.CollectAndCompare(metrics, strings.NewReader(expected), "rds_api_call_total")
+	require.NoError(t, err, "should label AWS API calls with the API name of the context")
+}
+
+func TestThrottledRequests(t *testing.T) {
+	metrics := awsapi.NewMetrics()
+
+	client := newRDSClient(metrics, httpClient{
+		statusCode: http.StatusBadRequest,
+		body:       `<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`,
+	}, 3)
+
+	_, err := client.DescribeDBInstances(context.TODO(), &aws_rds.DescribeDBInstancesInput{})
+	require.Error(t, err, "DescribeDBInstances must fail")
+	assert.True(t, awsapi.IsThrottle(err), "should detect throttling errors")
+
+	expected := `
+# HELP rds_exporter_aws_throttled_requests_total Total number of AWS API requests throttled by AWS, including retried requests
+# TYPE rds_exporter_aws_throttled_requests_total counter
+rds_exporter_aws_throttled_requests_total{api="rds",operation="DescribeDBInstances"} 3
+`
+
+	err = testutil.CollectAndCompare(metrics, strings.NewReader(expected), "rds_exporter_aws_throttled_requests_total")
+	require.NoError(t, err, "should count each throttled attempt")
+
+	expected = `
+# HELP rds_api_call_total Number of call to AWS API
+# TYPE rds_api_call_total counter
+rds_api_call_total{api="rds",aws_account_id="",aws_region="eu-west-3",operation="DescribeDBInstances",status_code="400"} 3
+`
+
+	err = testutil.CollectAndCompare(metrics, strings.NewReader(expected), "rds_api_call_total")
+	require.NoError(t, err, "should count each attempt as an AWS API call")
+}
+
+func TestThrottledRequestsIgnoreOtherErrors(t *testing.T) {
+	metrics := awsapi.NewMetrics()
+
+	client := newRDSClient(metrics, httpClient{
+		statusCode: http.StatusForbidden,
+		body:       `<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code><Message>User is not authorized</Message></Error></ErrorResponse>`,
+	}, 3)
+
+	_, err := client.DescribeDBInstances(context.TODO(), &aws_rds.DescribeDBInstancesInput{})
+	require.Error(t, err, "DescribeDBInstances must fail")
+	assert.False(t, awsapi.IsThrottle(err), "should not consider access denied as throttling")
+
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics, "rds_exporter_aws_throttled_requests_total"), "should not count other errors")
+}
+
+func TestNewRetryer(t *testing.T) {
+	testCases := []struct {
+		name                string
+		configuration       awsapi.RetryConfiguration
+		expectedMaxAttempts int
+		expectError         bool
+	}{
+		{
+			name:                "SDK defaults",
+			configuration:       awsapi.RetryConfiguration{},
+			expectedMaxAttempts: retry.DefaultMaxAttempts,
+		},
+		{
+			name:                "Standard mode",
+			configuration:       awsapi.RetryConfiguration{Mode: "standard", MaxAttempts: 5},
+			expectedMaxAttempts: 5,
+		},
+		{
+			name:                "Adaptive mode",
+			configuration:       awsapi.RetryConfiguration{Mode: "adaptive", MaxAttempts: 10, MaxBackoff: time.Minute},
+			expectedMaxAttempts: 10,
+		},
+		{
+			name:          "Unknown mode",
+			configuration: awsapi.RetryConfiguration{Mode: "legacy"},
+			expectError:   true,
+		},
+		{
+			name:          "Negative max attempts",
+			configuration: awsapi.RetryConfiguration{MaxAttempts: -1},
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retryer, err := awsapi.NewRetryer(tc.configuration)
+			if tc.expectError {
+				require.Error(t, err, "NewRetryer must fail")
+
+				return
+			}
+
+			require.NoError(t, err, "NewRetryer must succeed")
+			assert.Equal(t, tc.expectedMaxAttempts, retryer().MaxAttempts(), "Unexpected max attempts")
+		})
+	}
+}
+
+func TestRetryConfigurationMerge(t *testing.T) {
+	defaults := awsapi.RetryConfiguration{Mode: "standard", MaxAttempts: 3, MaxBackoff: 20 * time.Second}
+
+	merged := awsapi.RetryConfiguration{Mode: "adaptive"}.Merge(defaults)
+
+	assert.Equal(t, awsapi.RetryConfiguration{Mode: "adaptive", MaxAttempts: 3, MaxBackoff: 20 * time.Second}, merged, "should override defaults with set values only")
+}
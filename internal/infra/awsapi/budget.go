@@ -0,0 +1,182 @@
+package awsapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const budgetMiddlewareID = "PrometheusRDSExporterBudget"
+
+// BudgetConfiguration defines the AWS API calls budget of an AWS service
+type BudgetConfiguration struct {
+	Rate  float64 // Calls per second
+	Burst int     // Maximum number of calls without waiting
+}
+
+// Budget limits AWS API calls of each AWS service with token buckets
+// AWS API quotas apply per AWS account and region, so each AWS account and region has its own token buckets
+type Budget struct {
+	configurations map[string]BudgetConfiguration // AWS service name => budget
+
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket // AWS account, region and service => token bucket
+	now     func() time.Time
+}
+
+func NewBudget(configurations map[string]BudgetConfiguration) (*Budget, error) {
+	for service, configuration := range configurations {
+		if configuration.Rate <= 0 {
+			return nil, fmt.Errorf("invalid budget rate %v of %s, must be positive", configuration.Rate, service)
+		}
+
+		if configuration.Burst < 1 {
+			return nil, fmt.Errorf("invalid budget burst %d of %s, must be positive", configuration.Burst, service)
+		}
+	}
+
+	return &Budget{
+		configurations: configurations,
+		buckets:        make(map[string]*tokenBucket),
+		now:            time.Now,
+	}, nil
+}
+
+// getBucket returns the token bucket of the AWS service, or nil if the AWS service has no budget
+func (b *Budget) getBucket(awsAccountID string, awsRegion string, service string) *tokenBucket {
+	configuration, found := b.configurations[service]
+	if !found {
+		return nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	key := awsAccountID + "/" + awsRegion + "/" + service
+
+	bucket, found := b.buckets[key]
+	if !found {
+		bucket = newTokenBucket(configuration.Rate, configuration.Burst, b.now)
+		b.buckets[key] = bucket
+	}
+
+	return bucket
+}
+
+// Available returns true if the budget of the AWS service allows calls without waiting
+func (b *Budget) Available(awsAccountID string, awsRegion string, service string, calls int) bool {
+	bucket := b.getBucket(awsAccountID, awsRegion, service)
+	if bucket == nil {
+		return true
+	}
+
+	return bucket.available() >= float64(calls)
+}
+
+// AddMiddlewares adds the budget middleware to the AWS SDK client stack
+// The middleware runs after the retry middleware so retried calls also consume the budget
+func (b *Budget) AddMiddlewares(stack *middleware.Stack) error {
+	err := stack.Finalize.Insert(b, "Retry", middleware.After)
+	if err != nil {
+		return fmt.Errorf("can't add AWS API budget middleware: %w", err)
+	}
+
+	return nil
+}
+
+func (b *Budget) ID() string {
+	return budgetMiddlewareID
+}
+
+// HandleFinalize waits for the budget of the AWS service before calling AWS API
+func (b *Budget) HandleFinalize(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	bucket := b.getBucket(getAccountID(ctx), awsmiddleware.GetRegion(ctx), ServiceName(awsmiddleware.GetServiceID(ctx)))
+	if bucket != nil {
+		err := bucket.wait(ctx)
+		if err != nil {
+			return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("can't wait for AWS API budget: %w", err)
+		}
+	}
+
+	return next.HandleFinalize(ctx, in)
+}
+
+// tokenBucket refills tokens at a constant rate up to its burst
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now(),
+		now:    now,
+	}
+}
+
+// refill adds tokens accumulated since the last refill, must be called with mutex held
+func (t *tokenBucket) refill() {
+	now := t.now()
+
+	t.tokens = math.Min(t.burst, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	t.last = now
+}
+
+func (t *tokenBucket) available() float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.refill()
+
+	return t.tokens
+}
+
+// reserve takes a token and returns the delay before the token can be used
+func (t *tokenBucket) reserve() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.refill()
+	t.tokens--
+
+	if t.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-t.tokens / t.rate * float64(time.Second))
+}
+
+// wait blocks until a token is available or the context is cancelled
+func (t *tokenBucket) wait(ctx context.Context) error {
+	delay := t.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give back the token that won't be used
+		t.mutex.Lock()
+		t.tokens++
+		t.mutex.Unlock()
+
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,81 @@
+package awsapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestTokenBucket(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	bucket := newTokenBucket(2, 4, clock.Now)
+
+	assert.InDelta(t, 4, bucket.available(), 0.001, "should start with a full bucket")
+
+	for range 4 {
+		assert.Zero(t, bucket.reserve(), "should not wait while tokens are available")
+	}
+
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve(), "should wait for the next token when the bucket is empty")
+
+	clock.now = clock.now.Add(time.Second)
+	assert.InDelta(t, 1, bucket.available(), 0.001, "should refill tokens at the configured rate")
+
+	clock.now = clock.now.Add(time.Hour)
+	assert.InDelta(t, 4, bucket.available(), 0.001, "should not refill over burst")
+}
+
+func TestTokenBucketWaitCancellation(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	bucket := newTokenBucket(0.001, 1, clock.Now)
+
+	require.NoError(t, bucket.wait(context.TODO()), "should not wait while tokens are available")
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	require.ErrorIs(t, bucket.wait(ctx), context.Canceled, "should stop waiting when the context is cancelled")
+	assert.InDelta(t, 0, bucket.available(), 0.001, "should give back the unused token")
+}
+
+func TestBudgetAvailable(t *testing.T) {
+	budget, err := NewBudget(map[string]BudgetConfiguration{
+		RDSService: {Rate: 1, Burst: 10},
+	})
+	require.NoError(t, err, "NewBudget must succeed")
+
+	clock := &fakeClock{now: time.Now()}
+	budget.now = clock.Now
+
+	assert.True(t, budget.Available("123456789012", "eu-west-3", RDSService, 10), "should allow calls up to burst")
+	assert.False(t, budget.Available("123456789012", "eu-west-3", RDSService, 11), "should not allow calls over the remaining budget")
+	assert.True(t, budget.Available("123456789012", "eu-west-3", EC2Service, 1000), "should not limit AWS services without budget")
+
+	bucket := budget.getBucket("123456789012", "eu-west-3", RDSService)
+	for range 10 {
+		bucket.reserve()
+	}
+
+	assert.False(t, budget.Available("123456789012", "eu-west-3", RDSService, 1), "should report exhausted budget")
+	assert.True(t, budget.Available("123456789012", "eu-west-1", RDSService, 10), "should have a budget per AWS region")
+}
+
+func TestNewBudgetValidation(t *testing.T) {
+	_, err := NewBudget(map[string]BudgetConfiguration{RDSService: {Rate: 0, Burst: 10}})
+	require.Error(t, err, "should reject budgets without rate")
+
+	_, err = NewBudget(map[string]BudgetConfiguration{RDSService: {Rate: 1, Burst: 0}})
+	require.Error(t, err, "should reject budgets without burst")
+}
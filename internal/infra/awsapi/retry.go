@@ -0,0 +1,70 @@
+package awsapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// RetryConfiguration defines how AWS API calls are retried
+// Zero values use AWS SDK defaults
+type RetryConfiguration struct {
+	Mode        string // standard or adaptive
+	MaxAttempts int
+	MaxBackoff  time.Duration
+}
+
+// Merge returns the configuration with unset values replaced by values of defaults
+func (c RetryConfiguration) Merge(defaults RetryConfiguration) RetryConfiguration {
+	if c.Mode == "" {
+		c.Mode = defaults.Mode
+	}
+
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = defaults.MaxBackoff
+	}
+
+	return c
+}
+
+// NewRetryer returns a function creating AWS SDK retryers for the configuration
+func NewRetryer(configuration RetryConfiguration) (func() aws.Retryer, error) {
+	if configuration.MaxAttempts < 0 {
+		return nil, fmt.Errorf("invalid retry max attempts %d, must be positive", configuration.MaxAttempts)
+	}
+
+	if configuration.MaxBackoff < 0 {
+		return nil, fmt.Errorf("invalid retry max backoff %s, must be positive", configuration.MaxBackoff)
+	}
+
+	standardOptions := func(o *retry.StandardOptions) {
+		if configuration.MaxAttempts > 0 {
+			o.MaxAttempts = configuration.MaxAttempts
+		}
+
+		if configuration.MaxBackoff > 0 {
+			o.MaxBackoff = configuration.MaxBackoff
+		}
+	}
+
+	switch configuration.Mode {
+	case "", string(aws.RetryModeStandard):
+		return func() aws.Retryer {
+			return retry.NewStandard(standardOptions)
+		}, nil
+	case string(aws.RetryModeAdaptive):
+		return func() aws.Retryer {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, standardOptions)
+			})
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid retry mode %q, must be %s or %s", configuration.Mode, aws.RetryModeStandard, aws.RetryModeAdaptive)
+	}
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RefreshPath is the admin endpoint forcing an immediate refresh of metrics
+const RefreshPath = "/-/refresh"
+
+// Refresher refreshes metrics from AWS APIs
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+type refreshHandler struct {
+	refresher   Refresher
+	timeout     time.Duration // Maximum duration of a refresh, no deadline when zero
+	minInterval time.Duration // Minimum duration between the end of a refresh and the next forced refresh
+	now         func() time.Time
+
+	mutex       sync.Mutex
+	running     bool
+	lastRefresh time.Time
+}
+
+func NewRefreshHandler(refresher Refresher, timeout time.Duration, minInterval time.Duration) *refreshHandler {
+	return &refreshHandler{
+		refresher:   refresher,
+		timeout:     timeout,
+		minInterval: minInterval,
+		now:         time.Now,
+	}
+}
+
+// acquire reserves the refresh, or returns the delay before a refresh is allowed
+func (h *refreshHandler) acquire() (time.Duration, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.running {
+		return h.minInterval, false
+	}
+
+	if wait := h.minInterval - h.now().Sub(h.lastRefresh); !h.lastRefresh.IsZero() && wait > 0 {
+		return wait, false
+	}
+
+	h.running = true
+
+	return 0, true
+}
+
+func (h *refreshHandler) release() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.running = false
+	h.lastRefresh = h.now()
+}
+
+func (h *refreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST requests are allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	// Forced refreshes are rate limited since each one queries all AWS APIs
+	wait, acquired := h.acquire()
+	if !acquired {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(w, "a refresh is running or recently finished", http.StatusTooManyRequests)
+
+		return
+	}
+
+	defer h.release()
+
+	// The refresh is shared with scrapes, so a disconnected client must not cancel it
+	ctx := context.WithoutCancel(r.Context())
+
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	err := h.refresher.Refresh(ctx)
+	if err != nil {
+		http.Error(w, "refresh failed: "+err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
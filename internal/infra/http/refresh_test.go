@@ -0,0 +1,99 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	exporterhttp "github.com/qonto/prometheus-rds-exporter/internal/infra/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refresherFunc refreshes with a function
+type refresherFunc func(ctx context.Context) error
+
+func (f refresherFunc) Refresh(ctx context.Context) error {
+	return f(ctx)
+}
+
+func postRefresh(ctx context.Context, handler http.Handler) *httptest.ResponseRecorder {
+	request := httptest.NewRequestWithContext(ctx, http.MethodPost, exporterhttp.RefreshPath, nil)
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestRefreshHandlerIsDetachedFromRequest(t *testing.T) {
+	t.Parallel()
+
+	var refreshErr, deadlineSet bool
+
+	refresher := refresherFunc(func(ctx context.Context) error {
+		refreshErr = ctx.Err() != nil
+		_, deadlineSet = ctx.Deadline()
+
+		return nil
+	})
+
+	handler := exporterhttp.NewRefreshHandler(refresher, time.Minute, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Client disconnected
+
+	response := postRefresh(ctx, handler)
+	assert.Equal(t, http.StatusOK, response.Code, "Refresh must succeed")
+	assert.False(t, refreshErr, "Refresh must not be cancelled by the request")
+	assert.True(t, deadlineSet, "Refresh must be bounded by the timeout")
+}
+
+func TestRefreshHandlerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	refresher := refresherFunc(func(ctx context.Context) error {
+		close(started)
+		<-release
+
+		return errors.New("refresh failed")
+	})
+
+	handler := exporterhttp.NewRefreshHandler(refresher, 0, time.Hour)
+
+	done := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		done <- postRefresh(context.Background(), handler)
+	}()
+
+	<-started
+
+	response := postRefresh(context.Background(), handler)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code, "Refresh must be rejected while a refresh is running")
+	assert.Equal(t, "3600", response.Header().Get("Retry-After"), "Retry-After mismatch")
+
+	close(release)
+	require.Equal(t, http.StatusInternalServerError, (<-done).Code, "Refresh errors must be returned")
+
+	response = postRefresh(context.Background(), handler)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code, "Refresh must be rejected before the minimum interval")
+}
+
+func TestRefreshHandlerMethod(t *testing.T) {
+	t.Parallel()
+
+	handler := exporterhttp.NewRefreshHandler(refresherFunc(func(context.Context) error { return nil }), 0, 0)
+
+	request := httptest.NewRequest(http.MethodGet, exporterhttp.RefreshPath, nil)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, response.Code, "Only POST requests must be allowed")
+}
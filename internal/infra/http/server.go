@@ -35,11 +35,14 @@ type Component struct {
 }
 
 type Config struct {
-	MetricPath        string
-	ListenAddress     string
-	TLSKeyPath        string
-	TLSCertPath       string
-	OTELTracesEnabled bool
+	MetricPath         string
+	ListenAddress      string
+	TLSKeyPath         string
+	TLSCertPath        string
+	OTELTracesEnabled  bool
+	Refresher          Refresher     // Optional, exposes the refresh admin endpoint when set
+	RefreshTimeout     time.Duration // Maximum duration of forced refreshes, no deadline when zero
+	RefreshMinInterval time.Duration // Minimum interval between forced refreshes
 }
 
 func New(logger slog.Logger, config Config) (component Component) {
@@ -88,6 +91,10 @@ func (c *Component) Start() error {
 	http.Handle("/", otelhttp.NewHandler(homepage, "homepage"))
 	http.Handle(c.config.MetricPath, promhttp.Handler())
 
+	if c.config.Refresher != nil {
+		http.Handle(RefreshPath, otelhttp.NewHandler(NewRefreshHandler(c.config.Refresher, c.config.RefreshTimeout, c.config.RefreshMinInterval), "refresh"))
+	}
+
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(
 		signalChan,